@@ -1,15 +1,81 @@
 package main
 
 import (
+	"log"
+	"os"
+
 	"echo-api/internal/handler"
 
 	"github.com/aws/aws-lambda-go/lambda"
 )
 
 func main() {
-	// Create a new Lambda handler
-	h := handler.NewLambdaHandler()
-	
-	// Start the Lambda function
-	lambda.Start(h.HandleRequest)
-}
\ No newline at end of file
+	// HANDLER_MODE selects the event source the function serves
+	switch mode := os.Getenv("HANDLER_MODE"); mode {
+	case "", "proxy":
+		// Create a new Lambda handler
+		h := handler.NewLambdaHandler()
+
+		// Start the Lambda function
+		lambda.Start(h.HandleRequest)
+	case "functionurl":
+		// Function URL with InvokeMode BUFFERED
+		lambda.Start(handler.NewFunctionURLHandler().HandleRequest)
+	case "stream":
+		// Function URL with InvokeMode RESPONSE_STREAM
+		lambda.Start(handler.NewStreamingHandler().HandleRequest)
+	case "websocket":
+		// API Gateway WebSocket API ($connect, $disconnect, $default)
+		lambda.Start(handler.NewWebSocketHandler().HandleRequest)
+	case "sqs":
+		lambda.Start(handler.NewSQSHandler().HandleRequest)
+	case "sns":
+		lambda.Start(handler.NewSNSHandler().HandleRequest)
+	case "eventbridge":
+		// EventBridge rules, including scheduled rules
+		lambda.Start(handler.NewEventBridgeHandler().HandleRequest)
+	case "pipes":
+		// EventBridge Pipes target or enrichment
+		lambda.Start(handler.NewPipesHandler().HandleRequest)
+	case "kinesis":
+		lambda.Start(handler.NewKinesisHandler().HandleRequest)
+	case "dynamodb":
+		// DynamoDB Streams
+		lambda.Start(handler.NewDynamoDBHandler().HandleRequest)
+	case "s3":
+		lambda.Start(handler.NewS3Handler().HandleRequest)
+	case "authorizer-token":
+		// API Gateway TOKEN custom authorizer
+		lambda.Start(handler.NewAuthorizerHandler().HandleToken)
+	case "authorizer-request":
+		// API Gateway REQUEST custom authorizer
+		lambda.Start(handler.NewAuthorizerHandler().HandleRequest)
+	case "cognito":
+		// Cognito User Pool triggers; one function can serve every trigger
+		lambda.Start(handler.NewCognitoHandler().HandleRequest)
+	case "appsync":
+		// AppSync direct Lambda resolver, single or batch invocation
+		lambda.Start(handler.NewAppSyncHandler().HandleRequest)
+	case "stepfunctions":
+		// Step Functions task state invoking the function directly
+		lambda.Start(handler.NewStepFunctionsHandler().HandleRequest)
+	case "cloudfront":
+		// Lambda@Edge viewer and origin events
+		lambda.Start(handler.NewCloudFrontHandler().HandleRequest)
+	case "kafka":
+		// Amazon MSK and self-managed Kafka
+		lambda.Start(handler.NewKafkaHandler().HandleRequest)
+	case "activemq":
+		lambda.Start(handler.NewMQHandler().HandleActiveMQ)
+	case "rabbitmq":
+		lambda.Start(handler.NewMQHandler().HandleRabbitMQ)
+	case "iot":
+		// IoT Core rule action
+		lambda.Start(handler.NewIoTHandler().HandleRequest)
+	case "ses":
+		// SES receipt rule Lambda action
+		lambda.Start(handler.NewSESHandler().HandleRequest)
+	default:
+		log.Fatalf("unknown HANDLER_MODE %q", mode)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"echo-api/internal/handler"
+	"echo-api/internal/server"
+)
+
+func main() {
+	// Listen on PORT (default 8080)
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           server.New(handler.NewLambdaHandler()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Echo API listening on %s", srv.Addr)
+	log.Fatal(srv.ListenAndServe())
+}
@@ -2,4 +2,18 @@ module echo-api
 
 go 1.21
 
-require github.com/aws/aws-lambda-go v1.41.0
+require (
+	github.com/andybalholm/brotli v1.1.1
+	github.com/aws/aws-lambda-go v1.41.0
+	github.com/fxamacker/cbor/v2 v2.7.0
+	github.com/gorilla/websocket v1.5.3
+	github.com/klauspost/compress v1.17.11
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	google.golang.org/protobuf v1.34.2
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+)
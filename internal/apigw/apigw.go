@@ -0,0 +1,115 @@
+package apigw
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"echo-api/internal/sigv4"
+)
+
+// ErrGone is returned when the connection no longer exists
+var ErrGone = errors.New("connection is gone")
+
+// Poster sends data to a WebSocket connection through the API Gateway management API
+type Poster interface {
+	PostToConnection(ctx context.Context, endpoint, connectionID string, data []byte) error
+}
+
+// Endpoint returns the management API endpoint for a WebSocket API domain and stage
+func Endpoint(domainName, stage string) string {
+	return "https://" + domainName + "/" + stage
+}
+
+// Client calls the management API over HTTPS with SigV4 signed requests
+type Client struct {
+	http   *http.Client
+	signer *sigv4.Signer
+}
+
+// NewClient creates a new Client that signs requests with signer
+func NewClient(signer *sigv4.Signer) *Client {
+	return &Client{
+		http:   &http.Client{Timeout: 10 * time.Second},
+		signer: signer,
+	}
+}
+
+// PostToConnection posts data to the connection; it returns ErrGone when the client has disconnected
+func (c *Client) PostToConnection(ctx context.Context, endpoint, connectionID string, data []byte) error {
+	target, err := url.Parse(strings.TrimSuffix(endpoint, "/") + "/@connections/")
+	if err != nil {
+		return fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	target.Path += connectionID
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	c.signer.Sign(request, data)
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return fmt.Errorf("post to connection failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusGone:
+		return ErrGone
+	case response.StatusCode >= 300:
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("post to connection returned %d: %s", response.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// MemoryPoster is an in-memory stand-in for the management API
+type MemoryPoster struct {
+	mu       sync.Mutex
+	messages map[string][][]byte
+	gone     map[string]bool
+}
+
+// NewMemoryPoster creates a new MemoryPoster
+func NewMemoryPoster() *MemoryPoster {
+	return &MemoryPoster{
+		messages: make(map[string][][]byte),
+		gone:     make(map[string]bool),
+	}
+}
+
+// PostToConnection records data for the connection
+func (m *MemoryPoster) PostToConnection(ctx context.Context, endpoint, connectionID string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.gone[connectionID] {
+		return ErrGone
+	}
+	m.messages[connectionID] = append(m.messages[connectionID], append([]byte(nil), data...))
+	return nil
+}
+
+// Disconnect marks the connection as gone so later posts fail with ErrGone
+func (m *MemoryPoster) Disconnect(connectionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gone[connectionID] = true
+}
+
+// Messages returns the data posted to the connection
+func (m *MemoryPoster) Messages(connectionID string) [][]byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([][]byte(nil), m.messages[connectionID]...)
+}
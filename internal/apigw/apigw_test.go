@@ -0,0 +1,58 @@
+package apigw
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"echo-api/internal/sigv4"
+)
+
+func TestClient_PostToConnection(t *testing.T) {
+	var gotPath, gotBody, gotAuthorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuthorization = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		if strings.HasSuffix(r.URL.Path, "/gone") {
+			w.WriteHeader(http.StatusGone)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(sigv4.NewSigner(sigv4.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, "us-east-1", "execute-api"))
+
+	if err := client.PostToConnection(context.Background(), server.URL+"/prod", "abc=", []byte(`{"a":1}`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gotPath != "/prod/@connections/abc=" || gotBody != `{"a":1}` {
+		t.Errorf("Unexpected request %s %q", gotPath, gotBody)
+	}
+	if !strings.HasPrefix(gotAuthorization, "AWS4-HMAC-SHA256 Credential=AKID/") {
+		t.Errorf("Expected a SigV4 Authorization header, got %q", gotAuthorization)
+	}
+
+	if err := client.PostToConnection(context.Background(), server.URL+"/prod", "gone", nil); err != ErrGone {
+		t.Errorf("Expected ErrGone, got %v", err)
+	}
+}
+
+func TestMemoryPoster(t *testing.T) {
+	poster := NewMemoryPoster()
+	ctx := context.Background()
+
+	poster.PostToConnection(ctx, "", "c1", []byte("one"))
+	poster.PostToConnection(ctx, "", "c1", []byte("two"))
+	if messages := poster.Messages("c1"); len(messages) != 2 || string(messages[1]) != "two" {
+		t.Errorf("Expected two recorded messages, got %q", messages)
+	}
+
+	poster.Disconnect("c1")
+	if err := poster.PostToConnection(ctx, "", "c1", []byte("three")); err != ErrGone {
+		t.Errorf("Expected ErrGone after disconnect, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package authorizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+
+	"echo-api/internal/models"
+)
+
+const (
+	// EffectAllow grants access to the API
+	EffectAllow = "Allow"
+	// EffectDeny refuses access with 403
+	EffectDeny = "Deny"
+	// EffectUnauthorized refuses access with 401
+	EffectUnauthorized = "Unauthorized"
+)
+
+// Rule grants an effect to requests that meet every condition it sets
+type Rule struct {
+	Name         string            `json:"name"`
+	Effect       string            `json:"effect"`
+	PrincipalID  string            `json:"principalId"`
+	TokenPattern string            `json:"tokenPattern"`
+	Headers      map[string]string `json:"headers"`
+	SourceCIDRs  []string          `json:"sourceCidrs"`
+
+	token    *regexp.Regexp
+	headers  map[string]*regexp.Regexp
+	networks []*net.IPNet
+}
+
+// Config holds the rules, evaluated in order, and the effect used when none matches
+type Config struct {
+	Rules         []*Rule `json:"rules"`
+	DefaultEffect string  `json:"defaultEffect"`
+}
+
+// Input is what the authorizer knows about a request; TOKEN authorizers only see the token
+type Input struct {
+	Token    string
+	Headers  map[string]string
+	SourceIP string
+}
+
+// Decision is the outcome of evaluating the rules
+type Decision struct {
+	Effect      string
+	PrincipalID string
+	Rule        string
+}
+
+// ConfigFromEnv loads rules from AUTHORIZER_RULES_FILE or AUTHORIZER_RULES; without either every request is denied,
+// the same default as a rules document without defaultEffect
+func ConfigFromEnv() (*Config, error) {
+	if path := os.Getenv("AUTHORIZER_RULES_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read AUTHORIZER_RULES_FILE: %w", err)
+		}
+		return ParseConfig(data)
+	}
+	if inline := os.Getenv("AUTHORIZER_RULES"); inline != "" {
+		return ParseConfig([]byte(inline))
+	}
+	return &Config{DefaultEffect: EffectDeny}, nil
+}
+
+// ParseConfig parses and compiles a rules document
+func ParseConfig(data []byte) (*Config, error) {
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid authorizer rules: %w", err)
+	}
+
+	if config.DefaultEffect == "" {
+		config.DefaultEffect = EffectDeny
+	}
+	if !validEffect(config.DefaultEffect) {
+		return nil, fmt.Errorf("invalid default effect %q", config.DefaultEffect)
+	}
+
+	for index, rule := range config.Rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule-%d", index)
+		}
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+	}
+	return config, nil
+}
+
+// compile validates the rule and prepares its matchers
+func (r *Rule) compile() error {
+	if r.Effect == "" {
+		r.Effect = EffectAllow
+	}
+	if !validEffect(r.Effect) {
+		return fmt.Errorf("invalid effect %q", r.Effect)
+	}
+
+	if r.TokenPattern != "" {
+		pattern, err := regexp.Compile(r.TokenPattern)
+		if err != nil {
+			return fmt.Errorf("invalid token pattern: %w", err)
+		}
+		r.token = pattern
+	}
+
+	r.headers = make(map[string]*regexp.Regexp, len(r.Headers))
+	for name, value := range r.Headers {
+		pattern, err := regexp.Compile(value)
+		if err != nil {
+			return fmt.Errorf("invalid pattern for header %s: %w", name, err)
+		}
+		r.headers[strings.ToLower(name)] = pattern
+	}
+
+	for _, cidr := range r.SourceCIDRs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid source CIDR: %w", err)
+		}
+		r.networks = append(r.networks, network)
+	}
+	return nil
+}
+
+// matches reports whether the input meets every condition of the rule
+func (r *Rule) matches(input Input) bool {
+	if r.token != nil && !r.token.MatchString(input.Token) {
+		return false
+	}
+
+	for name, pattern := range r.headers {
+		value, ok := models.HeaderValue(input.Headers, name)
+		if !ok || !pattern.MatchString(value) {
+			return false
+		}
+	}
+
+	if len(r.networks) > 0 {
+		ip := net.ParseIP(input.SourceIP)
+		if ip == nil {
+			return false
+		}
+		inside := false
+		for _, network := range r.networks {
+			if network.Contains(ip) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			return false
+		}
+	}
+	return true
+}
+
+// Evaluate returns the decision of the first matching rule, or the default effect
+func (c *Config) Evaluate(input Input) Decision {
+	for _, rule := range c.Rules {
+		if rule.matches(input) {
+			return Decision{Effect: rule.Effect, PrincipalID: rule.PrincipalID, Rule: rule.Name}
+		}
+	}
+	return Decision{Effect: c.DefaultEffect}
+}
+
+// validEffect reports whether effect is a known effect
+func validEffect(effect string) bool {
+	return effect == EffectAllow || effect == EffectDeny || effect == EffectUnauthorized
+}
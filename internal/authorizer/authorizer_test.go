@@ -0,0 +1,66 @@
+package authorizer
+
+import (
+	"testing"
+)
+
+const rules = `{
+	"rules": [
+		{"name": "blocked-network", "effect": "Deny", "sourceCidrs": ["203.0.113.0/24"]},
+		{"name": "admin", "principalId": "admin", "tokenPattern": "^Bearer admin-", "headers": {"X-Tenant": "^acme$"}},
+		{"name": "reader", "principalId": "reader", "tokenPattern": "^Bearer "},
+		{"name": "missing-token", "effect": "Unauthorized", "tokenPattern": "^$"}
+	]
+}`
+
+func TestEvaluate(t *testing.T) {
+	config, err := ParseConfig([]byte(rules))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		input    Input
+		expected Decision
+	}{
+		{"blocked network wins", Input{Token: "Bearer admin-1", SourceIP: "203.0.113.9"}, Decision{EffectDeny, "", "blocked-network"}},
+		{"admin with tenant", Input{Token: "Bearer admin-1", Headers: map[string]string{"x-tenant": "acme"}}, Decision{EffectAllow, "admin", "admin"}},
+		{"admin without tenant falls through", Input{Token: "Bearer admin-1"}, Decision{EffectAllow, "reader", "reader"}},
+		{"no token", Input{}, Decision{EffectUnauthorized, "", "missing-token"}},
+		{"nothing matches", Input{Token: "Basic abc"}, Decision{EffectDeny, "", ""}},
+	}
+
+	for _, tc := range testCases {
+		if decision := config.Evaluate(tc.input); decision != tc.expected {
+			t.Errorf("%s: expected %+v, got %+v", tc.name, tc.expected, decision)
+		}
+	}
+}
+
+func TestParseConfig_Errors(t *testing.T) {
+	for _, document := range []string{
+		`not json`,
+		`{"defaultEffect": "Maybe"}`,
+		`{"rules": [{"effect": "Permit"}]}`,
+		`{"rules": [{"tokenPattern": "("}]}`,
+		`{"rules": [{"sourceCidrs": ["10.0.0.0"]}]}`,
+	} {
+		if _, err := ParseConfig([]byte(document)); err == nil {
+			t.Errorf("Expected error for %s", document)
+		}
+	}
+}
+
+func TestConfigFromEnv_DeniesByDefault(t *testing.T) {
+	t.Setenv("AUTHORIZER_RULES_FILE", "")
+	t.Setenv("AUTHORIZER_RULES", "")
+
+	config, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decision := config.Evaluate(Input{}); decision.Effect != EffectDeny {
+		t.Errorf("Expected Deny without rules, got %+v", decision)
+	}
+}
@@ -0,0 +1,119 @@
+package byterange
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxRanges caps how many ranges a single request may ask for
+const MaxRanges = 16
+
+var (
+	// ErrInvalid means the Range header is malformed and must be ignored
+	ErrInvalid = errors.New("invalid range header")
+	// ErrUnsatisfiable means no requested range overlaps the representation
+	ErrUnsatisfiable = errors.New("range not satisfiable")
+)
+
+// Range is an inclusive byte range
+type Range struct {
+	Start int64
+	End   int64
+}
+
+// Length returns the number of bytes in the range
+func (r Range) Length() int64 {
+	return r.End - r.Start + 1
+}
+
+// ContentRange formats the Content-Range header value for a representation of size bytes
+func (r Range) ContentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.Start, r.End, size)
+}
+
+// UnsatisfiedContentRange formats the Content-Range header value sent with 416 responses
+func UnsatisfiedContentRange(size int64) string {
+	return fmt.Sprintf("bytes */%d", size)
+}
+
+// Parse parses a Range header such as "bytes=0-99, 200-, -50" against a representation of size bytes;
+// unsatisfiable ranges are dropped, and ErrUnsatisfiable is returned when none remain
+func Parse(header string, size int64) ([]Range, error) {
+	unit, set, ok := strings.Cut(strings.TrimSpace(header), "=")
+	if !ok || !strings.EqualFold(strings.TrimSpace(unit), "bytes") {
+		return nil, ErrInvalid
+	}
+
+	specs := strings.Split(set, ",")
+	if len(specs) > MaxRanges {
+		return nil, ErrInvalid
+	}
+
+	var ranges []Range
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		first, last, ok := strings.Cut(spec, "-")
+		if !ok {
+			return nil, ErrInvalid
+		}
+
+		if first == "" {
+			// Suffix range: the last N bytes
+			suffix, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || suffix < 0 {
+				return nil, ErrInvalid
+			}
+			if suffix == 0 || size == 0 {
+				continue
+			}
+			if suffix > size {
+				suffix = size
+			}
+			ranges = append(ranges, Range{Start: size - suffix, End: size - 1})
+			continue
+		}
+
+		start, err := strconv.ParseInt(first, 10, 64)
+		if err != nil || start < 0 {
+			return nil, ErrInvalid
+		}
+		end := size - 1
+		if last != "" {
+			end, err = strconv.ParseInt(last, 10, 64)
+			if err != nil || end < start {
+				return nil, ErrInvalid
+			}
+			if end >= size {
+				end = size - 1
+			}
+		}
+		if start >= size {
+			continue
+		}
+		ranges = append(ranges, Range{Start: start, End: end})
+	}
+
+	if len(ranges) == 0 {
+		return nil, ErrUnsatisfiable
+	}
+	return ranges, nil
+}
+
+// Multipart renders a multipart/byteranges body for the ranges of data
+func Multipart(data []byte, ranges []Range, contentType, boundary string) []byte {
+	size := int64(len(data))
+
+	var buf bytes.Buffer
+	for _, r := range ranges {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s\r\n", contentType)
+		fmt.Fprintf(&buf, "Content-Range: %s\r\n\r\n", r.ContentRange(size))
+		buf.Write(data[r.Start : r.End+1])
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	return buf.Bytes()
+}
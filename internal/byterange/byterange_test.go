@@ -0,0 +1,89 @@
+package byterange
+
+import (
+	"io"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		header   string
+		expected []Range
+	}{
+		{"bytes=0-9", []Range{{0, 9}}},
+		{"bytes=90-", []Range{{90, 99}}},
+		{"bytes=-10", []Range{{90, 99}}},
+		{"bytes=-500", []Range{{0, 99}}},
+		{"bytes=50-500", []Range{{50, 99}}},
+		{"bytes=0-0, 10-19", []Range{{0, 0}, {10, 19}}},
+		{"BYTES = 5-5", []Range{{5, 5}}},
+		{"bytes=0-9, 200-300", []Range{{0, 9}}},
+	}
+
+	for _, tc := range testCases {
+		ranges, err := Parse(tc.header, 100)
+		if err != nil {
+			t.Errorf("For %q, unexpected error: %v", tc.header, err)
+			continue
+		}
+		if len(ranges) != len(tc.expected) {
+			t.Errorf("For %q, expected %v, got %v", tc.header, tc.expected, ranges)
+			continue
+		}
+		for i := range ranges {
+			if ranges[i] != tc.expected[i] {
+				t.Errorf("For %q, expected %v, got %v", tc.header, tc.expected, ranges)
+			}
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	testCases := []struct {
+		header   string
+		expected error
+	}{
+		{"items=0-9", ErrInvalid},
+		{"bytes=9-0", ErrInvalid},
+		{"bytes=abc", ErrInvalid},
+		{"bytes=a-b", ErrInvalid},
+		{"bytes=" + strings.Repeat("0-1,", MaxRanges) + "0-1", ErrInvalid},
+		{"bytes=100-", ErrUnsatisfiable},
+		{"bytes=-0", ErrUnsatisfiable},
+	}
+
+	for _, tc := range testCases {
+		if _, err := Parse(tc.header, 100); err != tc.expected {
+			t.Errorf("For %q, expected %v, got %v", tc.header, tc.expected, err)
+		}
+	}
+}
+
+func TestMultipart(t *testing.T) {
+	data := []byte("abcdefghijklmnopqrstuvwxyz")
+	body := Multipart(data, []Range{{0, 2}, {23, 25}}, "application/octet-stream", "sep")
+
+	reader := multipart.NewReader(strings.NewReader(string(body)), "sep")
+	expected := []struct {
+		contentRange string
+		content      string
+	}{
+		{"bytes 0-2/26", "abc"},
+		{"bytes 23-25/26", "xyz"},
+	}
+	for _, want := range expected {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("Failed to read part: %v", err)
+		}
+		content, _ := io.ReadAll(part)
+		if part.Header.Get("Content-Range") != want.contentRange || string(content) != want.content {
+			t.Errorf("Expected %s %q, got %s %q", want.contentRange, want.content, part.Header.Get("Content-Range"), content)
+		}
+	}
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("Expected end of multipart body, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETag is an entity tag as defined by RFC 9110
+type ETag struct {
+	Value string
+	Weak  bool
+}
+
+// String formats the tag for the ETag header
+func (e ETag) String() string {
+	if e.Weak {
+		return `W/"` + e.Value + `"`
+	}
+	return `"` + e.Value + `"`
+}
+
+// Compute derives an entity tag from a representation
+func Compute(representation []byte, weak bool) ETag {
+	sum := sha256.Sum256(representation)
+	return ETag{Value: hex.EncodeToString(sum[:16]), Weak: weak}
+}
+
+// ParseETag parses a single entity tag, accepting unquoted values for convenience
+func ParseETag(value string) ETag {
+	value = strings.TrimSpace(value)
+	weak := strings.HasPrefix(value, "W/")
+	value = strings.TrimPrefix(value, "W/")
+	return ETag{Value: strings.Trim(value, `"`), Weak: weak}
+}
+
+// parseList parses an If-Match or If-None-Match header; a nil result with true means "*"
+func parseList(header string) ([]ETag, bool) {
+	if strings.TrimSpace(header) == "*" {
+		return nil, true
+	}
+	var tags []ETag
+	for _, item := range strings.Split(header, ",") {
+		if strings.TrimSpace(item) != "" {
+			tags = append(tags, ParseETag(item))
+		}
+	}
+	return tags, false
+}
+
+// strongMatch compares tags with the strong comparison function used by If-Match
+func strongMatch(a, b ETag) bool {
+	return !a.Weak && !b.Weak && a.Value == b.Value
+}
+
+// weakMatch compares tags with the weak comparison function used by If-None-Match
+func weakMatch(a, b ETag) bool {
+	return a.Value == b.Value
+}
+
+// Resource describes the current state of the requested representation
+type Resource struct {
+	ETag         ETag
+	LastModified time.Time
+}
+
+// Evaluate applies the conditional request headers in RFC 9110 order; it returns
+// http.StatusOK to serve the representation, or http.StatusNotModified or http.StatusPreconditionFailed
+func Evaluate(method string, header func(string) string, resource Resource) int {
+	if ifMatch := header("If-Match"); ifMatch != "" {
+		if !matchesAny(ifMatch, resource.ETag, strongMatch) {
+			return http.StatusPreconditionFailed
+		}
+	} else if since, ok := parseDate(header("If-Unmodified-Since")); ok && !resource.LastModified.IsZero() {
+		if resource.LastModified.After(since) {
+			return http.StatusPreconditionFailed
+		}
+	}
+
+	if ifNoneMatch := header("If-None-Match"); ifNoneMatch != "" {
+		if matchesAny(ifNoneMatch, resource.ETag, weakMatch) {
+			if method == http.MethodGet || method == http.MethodHead {
+				return http.StatusNotModified
+			}
+			return http.StatusPreconditionFailed
+		}
+	} else if since, ok := parseDate(header("If-Modified-Since")); ok && !resource.LastModified.IsZero() {
+		if (method == http.MethodGet || method == http.MethodHead) && !resource.LastModified.After(since) {
+			return http.StatusNotModified
+		}
+	}
+
+	return http.StatusOK
+}
+
+// RangeApplies evaluates an If-Range header value; a Range header is honored only when it is
+// empty, a strong ETag matching the resource, or a date equal to its Last-Modified time
+func RangeApplies(ifRange string, resource Resource) bool {
+	ifRange = strings.TrimSpace(ifRange)
+	if ifRange == "" {
+		return true
+	}
+	if strings.HasPrefix(ifRange, "\"") || strings.HasPrefix(ifRange, "W/") {
+		return strongMatch(ParseETag(ifRange), resource.ETag)
+	}
+	date, ok := parseDate(ifRange)
+	return ok && !resource.LastModified.IsZero() && date.Equal(resource.LastModified.Truncate(time.Second))
+}
+
+// matchesAny reports whether the header lists the tag or is "*"
+func matchesAny(header string, tag ETag, match func(a, b ETag) bool) bool {
+	tags, any := parseList(header)
+	if any {
+		return true
+	}
+	for _, candidate := range tags {
+		if match(candidate, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDate parses an HTTP date header
+func parseDate(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := http.ParseTime(value)
+	return t, err == nil
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// headers returns a header lookup backed by a map
+func headers(values map[string]string) func(string) string {
+	return func(name string) string {
+		return values[name]
+	}
+}
+
+func TestETag(t *testing.T) {
+	strong := Compute([]byte("body"), false)
+	weak := Compute([]byte("body"), true)
+
+	if strong.Value != weak.Value {
+		t.Error("Expected weak and strong tags of the same body to share a value")
+	}
+	if weak.String() != `W/"`+weak.Value+`"` {
+		t.Errorf("Unexpected weak tag format %s", weak.String())
+	}
+	if parsed := ParseETag(weak.String()); parsed != weak {
+		t.Errorf("Expected %+v to round-trip, got %+v", weak, parsed)
+	}
+	if parsed := ParseETag("abc"); parsed.Value != "abc" || parsed.Weak {
+		t.Errorf("Expected unquoted value to parse as strong tag, got %+v", parsed)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	lastModified := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	resource := Resource{ETag: ETag{Value: "abc"}, LastModified: lastModified}
+	before := lastModified.Add(-time.Hour).Format(http.TimeFormat)
+	after := lastModified.Add(time.Hour).Format(http.TimeFormat)
+
+	testCases := []struct {
+		name     string
+		method   string
+		headers  map[string]string
+		expected int
+	}{
+		{"no conditions", "GET", map[string]string{}, http.StatusOK},
+		{"if-none-match hit", "GET", map[string]string{"If-None-Match": `"xyz", "abc"`}, http.StatusNotModified},
+		{"if-none-match weak hit", "GET", map[string]string{"If-None-Match": `W/"abc"`}, http.StatusNotModified},
+		{"if-none-match star", "GET", map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		{"if-none-match miss", "GET", map[string]string{"If-None-Match": `"xyz"`}, http.StatusOK},
+		{"if-none-match hit on POST", "POST", map[string]string{"If-None-Match": `"abc"`}, http.StatusPreconditionFailed},
+		{"if-match hit", "POST", map[string]string{"If-Match": `"abc"`}, http.StatusOK},
+		{"if-match weak never matches", "POST", map[string]string{"If-Match": `W/"abc"`}, http.StatusPreconditionFailed},
+		{"if-match miss", "GET", map[string]string{"If-Match": `"xyz"`}, http.StatusPreconditionFailed},
+		{"if-modified-since not modified", "GET", map[string]string{"If-Modified-Since": after}, http.StatusNotModified},
+		{"if-modified-since modified", "GET", map[string]string{"If-Modified-Since": before}, http.StatusOK},
+		{"if-none-match takes precedence", "GET", map[string]string{"If-None-Match": `"xyz"`, "If-Modified-Since": after}, http.StatusOK},
+		{"if-unmodified-since failed", "POST", map[string]string{"If-Unmodified-Since": before}, http.StatusPreconditionFailed},
+		{"if-unmodified-since passed", "POST", map[string]string{"If-Unmodified-Since": after}, http.StatusOK},
+		{"invalid date ignored", "GET", map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		if result := Evaluate(tc.method, headers(tc.headers), resource); result != tc.expected {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.expected, result)
+		}
+	}
+}
+
+func TestRangeApplies(t *testing.T) {
+	lastModified := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	resource := Resource{ETag: ETag{Value: "abc"}, LastModified: lastModified}
+
+	testCases := []struct {
+		ifRange  string
+		expected bool
+	}{
+		{"", true},
+		{`"abc"`, true},
+		{`"xyz"`, false},
+		{`W/"abc"`, false},
+		{lastModified.Format(http.TimeFormat), true},
+		{lastModified.Add(-time.Hour).Format(http.TimeFormat), false},
+		{"not a date", false},
+	}
+
+	for _, tc := range testCases {
+		if result := RangeApplies(tc.ifRange, resource); result != tc.expected {
+			t.Errorf("For If-Range %q, expected %v, got %v", tc.ifRange, tc.expected, result)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package cloudfront
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Event types of a Lambda@Edge trigger
+const (
+	ViewerRequest  = "viewer-request"
+	OriginRequest  = "origin-request"
+	OriginResponse = "origin-response"
+	ViewerResponse = "viewer-response"
+)
+
+// Event is the payload CloudFront sends to a Lambda@Edge function
+type Event struct {
+	Records []Record `json:"Records"`
+}
+
+// Record holds a single CloudFront event
+type Record struct {
+	CF CF `json:"cf"`
+}
+
+// CF is the cf object of a record; Response is only set for response events
+type CF struct {
+	Config   Config    `json:"config"`
+	Request  *Request  `json:"request"`
+	Response *Response `json:"response,omitempty"`
+}
+
+// Config identifies the distribution and the trigger
+type Config struct {
+	DistributionDomainName string `json:"distributionDomainName"`
+	DistributionID         string `json:"distributionId"`
+	EventType              string `json:"eventType"`
+	RequestID              string `json:"requestId"`
+}
+
+// Request is the request as CloudFront sees it; Origin is kept verbatim so it round-trips unchanged
+type Request struct {
+	ClientIP    string          `json:"clientIp"`
+	Method      string          `json:"method"`
+	URI         string          `json:"uri"`
+	Querystring string          `json:"querystring"`
+	Headers     Headers         `json:"headers"`
+	Body        *Body           `json:"body,omitempty"`
+	Origin      json.RawMessage `json:"origin,omitempty"`
+}
+
+// Body is the request body, only present when the trigger includes it
+type Body struct {
+	InputTruncated bool   `json:"inputTruncated"`
+	Action         string `json:"action"`
+	Encoding       string `json:"encoding"`
+	Data           string `json:"data"`
+}
+
+// Response is a response from the origin, or one generated by the function
+type Response struct {
+	Status            string  `json:"status"`
+	StatusDescription string  `json:"statusDescription,omitempty"`
+	Headers           Headers `json:"headers"`
+	Body              string  `json:"body,omitempty"`
+	BodyEncoding      string  `json:"bodyEncoding,omitempty"`
+}
+
+// Header is one value of a header; Key keeps the original case
+type Header struct {
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value"`
+}
+
+// Headers maps lowercase header names to their values, CloudFront's array form
+type Headers map[string][]Header
+
+// Get returns the first value of the header name
+func (h Headers) Get(name string) string {
+	if values := h[strings.ToLower(name)]; len(values) > 0 {
+		return values[0].Value
+	}
+	return ""
+}
+
+// Set replaces the header name with a single value
+func (h Headers) Set(name, value string) {
+	h[strings.ToLower(name)] = []Header{{Key: http.CanonicalHeaderKey(name), Value: value}}
+}
+
+// IsRequestEvent reports whether eventType is a viewer or origin request
+func IsRequestEvent(eventType string) bool {
+	return eventType == ViewerRequest || eventType == OriginRequest
+}
@@ -0,0 +1,47 @@
+package cloudfront
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const originRequestJSON = `{"Records": [{"cf": {
+	"config": {"distributionDomainName": "d111111abcdef8.cloudfront.net", "distributionId": "EDFDVBD6EXAMPLE", "eventType": "origin-request", "requestId": "4TyzHTaYWb1GX1qTfsHhEqV6HUDd_BzoBZnwfnvQc_1oF26ClkoUSEQ=="},
+	"request": {
+		"clientIp": "203.0.113.178", "method": "GET", "uri": "/", "querystring": "a=1",
+		"headers": {"host": [{"key": "Host", "value": "d111111abcdef8.cloudfront.net"}], "user-agent": [{"key": "User-Agent", "value": "curl/8.0"}]},
+		"origin": {"custom": {"customHeaders": {}, "domainName": "example.org", "keepaliveTimeout": 5, "path": "", "port": 443, "protocol": "https", "readTimeout": 30, "sslProtocols": ["TLSv1.2"]}}
+	}
+}}]}`
+
+func TestEvent_RoundTrip(t *testing.T) {
+	var event Event
+	if err := json.Unmarshal([]byte(originRequestJSON), &event); err != nil {
+		t.Fatalf("Failed to unmarshal event: %v", err)
+	}
+
+	request := event.Records[0].CF.Request
+	if !IsRequestEvent(event.Records[0].CF.Config.EventType) || request.Headers.Get("User-Agent") != "curl/8.0" {
+		t.Errorf("Unexpected event %+v", event)
+	}
+
+	// The origin must survive unchanged when the request is returned
+	data, err := json.Marshal(request)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+	if !strings.Contains(string(data), `"sslProtocols":["TLSv1.2"]`) {
+		t.Errorf("Expected the origin to round-trip, got %s", data)
+	}
+}
+
+func TestHeaders_Set(t *testing.T) {
+	headers := Headers{}
+	headers.Set("x-echo-event", "viewer-request")
+
+	values := headers["x-echo-event"]
+	if len(values) != 1 || values[0].Key != "X-Echo-Event" || values[0].Value != "viewer-request" {
+		t.Errorf("Unexpected headers %v", headers)
+	}
+}
@@ -0,0 +1,55 @@
+package codec
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// cborEncoding sorts map keys so the same echo always encodes to the same bytes
+var cborEncoding, _ = cbor.CanonicalEncOptions().EncMode()
+
+// MarshalMsgPack encodes v as MessagePack using its JSON shape
+func MarshalMsgPack(v interface{}) ([]byte, error) {
+	generic, err := ToGeneric(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetSortMapKeys(true)
+	if err := encoder.Encode(generic); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalCBOR encodes v as CBOR using its JSON shape
+func MarshalCBOR(v interface{}) ([]byte, error) {
+	generic, err := ToGeneric(v)
+	if err != nil {
+		return nil, err
+	}
+	return cborEncoding.Marshal(generic)
+}
+
+// decodeMsgPack decodes a MessagePack body
+func decodeMsgPack(data []byte) (interface{}, error) {
+	var value interface{}
+	if err := msgpack.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("invalid MessagePack: %w", err)
+	}
+	return normalize(value), nil
+}
+
+// decodeCBOR decodes a CBOR body
+func decodeCBOR(data []byte) (interface{}, error) {
+	var value interface{}
+	if err := cbor.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("invalid CBOR: %w", err)
+	}
+	return normalize(value), nil
+}
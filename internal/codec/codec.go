@@ -0,0 +1,95 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// decoders maps request media types to body decoders
+var decoders = map[string]func([]byte) (interface{}, error){
+	"application/json":                decodeJSON,
+	"application/msgpack":             decodeMsgPack,
+	"application/x-msgpack":           decodeMsgPack,
+	"application/vnd.msgpack":         decodeMsgPack,
+	"application/cbor":                decodeCBOR,
+	"application/x-protobuf":          decodeProtobuf,
+	"application/protobuf":            decodeProtobuf,
+	"application/vnd.google.protobuf": decodeProtobuf,
+}
+
+// DecodeBody decodes a request body according to its Content-Type; it returns nil for types it does not understand
+func DecodeBody(contentType string, body []byte) (interface{}, error) {
+	if contentType == "" || len(body) == 0 {
+		return nil, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, nil
+	}
+
+	decode, ok := decoders[mediaType]
+	if !ok {
+		switch {
+		case strings.HasSuffix(mediaType, "+json"):
+			decode = decodeJSON
+		case strings.HasSuffix(mediaType, "+cbor"):
+			decode = decodeCBOR
+		default:
+			return nil, nil
+		}
+	}
+	return decode(body)
+}
+
+// ToGeneric converts a JSON-tagged value into maps, slices and scalars with the same shape as its JSON encoding
+func ToGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(data)
+}
+
+// decodeJSON decodes JSON, keeping integers as integers
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return normalize(value), nil
+}
+
+// normalize converts decoded values into JSON-compatible types
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalize(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = normalize(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+		return v
+	}
+	return value
+}
@@ -0,0 +1,105 @@
+package codec
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestDecodeBody_JSON(t *testing.T) {
+	parsed, err := DecodeBody("application/json; charset=utf-8", []byte(`{"id": 12345678901, "ratio": 0.5, "tags": ["a"]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"id":    int64(12345678901),
+		"ratio": 0.5,
+		"tags":  []interface{}{"a"},
+	}
+	if !reflect.DeepEqual(parsed, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, parsed)
+	}
+
+	if parsed, _ := DecodeBody("application/vnd.api+json", []byte(`{"a":1}`)); parsed == nil {
+		t.Error("Expected +json suffix to be decoded")
+	}
+}
+
+func TestDecodeBody_Binary(t *testing.T) {
+	value := map[string]interface{}{"device": "sensor-1", "reading": 21}
+
+	packed, _ := msgpack.Marshal(value)
+	parsed, err := DecodeBody("application/msgpack", packed)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed.(map[string]interface{})["device"] != "sensor-1" {
+		t.Errorf("Expected msgpack body to decode, got %#v", parsed)
+	}
+
+	encoded, _ := cbor.Marshal(map[int]string{1: "one"})
+	parsed, err = DecodeBody("application/cbor", encoded)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed.(map[string]interface{})["1"] != "one" {
+		t.Errorf("Expected CBOR integer keys to become strings, got %#v", parsed)
+	}
+}
+
+func TestDecodeBody_Unsupported(t *testing.T) {
+	testCases := []struct {
+		contentType string
+		body        string
+	}{
+		{"", `{"a":1}`},
+		{"text/plain", "hello"},
+		{"application/json", ""},
+		{"not a media type;;", "x"},
+	}
+
+	for _, tc := range testCases {
+		parsed, err := DecodeBody(tc.contentType, []byte(tc.body))
+		if parsed != nil || err != nil {
+			t.Errorf("For %q, expected nil result, got %#v, %v", tc.contentType, parsed, err)
+		}
+	}
+
+	if _, err := DecodeBody("application/json", []byte("{")); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+	if _, err := DecodeBody("application/msgpack", []byte{0xc1}); err == nil {
+		t.Error("Expected error for invalid MessagePack")
+	}
+}
+
+func TestMarshalBinary_MatchesJSONShape(t *testing.T) {
+	value := struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+		Skip  string `json:"skip,omitempty"`
+	}{Name: "echo", Count: 2}
+
+	packed, err := MarshalMsgPack(value)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var fromMsgPack map[string]interface{}
+	msgpack.Unmarshal(packed, &fromMsgPack)
+	if fromMsgPack["name"] != "echo" || len(fromMsgPack) != 2 {
+		t.Errorf("Expected JSON keys in MessagePack, got %#v", fromMsgPack)
+	}
+
+	encoded, err := MarshalCBOR(value)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var fromCBOR map[string]interface{}
+	cbor.Unmarshal(encoded, &fromCBOR)
+	if fromCBOR["name"] != "echo" || len(fromCBOR) != 2 {
+		t.Errorf("Expected JSON keys in CBOR, got %#v", fromCBOR)
+	}
+}
@@ -0,0 +1,68 @@
+package codec
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// FromImage converts a DynamoDB item image into plain JSON values
+func FromImage(image map[string]events.DynamoDBAttributeValue) map[string]interface{} {
+	if image == nil {
+		return nil
+	}
+	values := make(map[string]interface{}, len(image))
+	for name, attribute := range image {
+		values[name] = FromAttributeValue(attribute)
+	}
+	return values
+}
+
+// FromAttributeValue converts a DynamoDB attribute value into a plain JSON value; numbers stay
+// exact as json.Number, binary values become base64 strings and sets become arrays
+func FromAttributeValue(attribute events.DynamoDBAttributeValue) interface{} {
+	switch attribute.DataType() {
+	case events.DataTypeString:
+		return attribute.String()
+	case events.DataTypeNumber:
+		return json.Number(attribute.Number())
+	case events.DataTypeBinary:
+		return base64.StdEncoding.EncodeToString(attribute.Binary())
+	case events.DataTypeBoolean:
+		return attribute.Boolean()
+	case events.DataTypeNull:
+		return nil
+	case events.DataTypeList:
+		list := attribute.List()
+		values := make([]interface{}, len(list))
+		for i, item := range list {
+			values[i] = FromAttributeValue(item)
+		}
+		return values
+	case events.DataTypeMap:
+		return FromImage(attribute.Map())
+	case events.DataTypeStringSet:
+		set := attribute.StringSet()
+		values := make([]interface{}, len(set))
+		for i, item := range set {
+			values[i] = item
+		}
+		return values
+	case events.DataTypeNumberSet:
+		set := attribute.NumberSet()
+		values := make([]interface{}, len(set))
+		for i, item := range set {
+			values[i] = json.Number(item)
+		}
+		return values
+	case events.DataTypeBinarySet:
+		set := attribute.BinarySet()
+		values := make([]interface{}, len(set))
+		for i, item := range set {
+			values[i] = base64.StdEncoding.EncodeToString(item)
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package codec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestFromImage(t *testing.T) {
+	var image map[string]events.DynamoDBAttributeValue
+	err := json.Unmarshal([]byte(`{
+		"id": {"S": "o1"},
+		"total": {"N": "12.50"},
+		"paid": {"BOOL": true},
+		"note": {"NULL": true},
+		"blob": {"B": "AQI="},
+		"tags": {"SS": ["a", "b"]},
+		"sizes": {"NS": ["1", "2"]},
+		"items": {"L": [{"M": {"sku": {"S": "x"}, "qty": {"N": "2"}}}]}
+	}`), &image)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal image: %v", err)
+	}
+
+	data, err := json.Marshal(FromImage(image))
+	if err != nil {
+		t.Fatalf("Failed to marshal plain image: %v", err)
+	}
+	expected := `{"blob":"AQI=","id":"o1","items":[{"qty":2,"sku":"x"}],"note":null,"paid":true,"sizes":[1,2],"tags":["a","b"],"total":12.50}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
@@ -0,0 +1,201 @@
+package codec
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"echo-api/internal/models"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// maxProtobufDepth bounds how deep schemaless decoding descends into nested messages; deeper
+// length-delimited fields are kept as base64
+const maxProtobufDepth = 32
+
+// coreFields are the EchoResponse JSON fields with a dedicated protobuf field
+var coreFields = map[string]bool{
+	"request":     true,
+	"message":     true,
+	"processedAt": true,
+}
+
+// MarshalProto encodes the response as the echo.v1.EchoResponse message defined in api/echo.proto
+func MarshalProto(response *models.EchoResponse) ([]byte, error) {
+	request, err := marshalProtoRequest(&response.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	data = protowire.AppendTag(data, 1, protowire.BytesType)
+	data = protowire.AppendBytes(data, request)
+	data = appendString(data, 2, response.Message)
+	data = appendString(data, 3, response.ProcessedAt)
+
+	// Every other section keeps its JSON encoding, so new sections need no schema change
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	sections := make(map[string]string)
+	for name, value := range fields {
+		if !coreFields[name] {
+			sections[name] = string(value)
+		}
+	}
+	data = appendStringMap(data, 4, sections)
+
+	return data, nil
+}
+
+// marshalProtoRequest encodes the echo.v1.EchoRequest message
+func marshalProtoRequest(request *models.EchoRequest) ([]byte, error) {
+	var data []byte
+	data = appendString(data, 1, request.Method)
+	data = appendString(data, 2, request.Path)
+	data = appendStringMap(data, 3, request.Headers)
+	data = appendStringMap(data, 4, request.QueryParams)
+	data = appendString(data, 5, request.Body)
+	if request.IsBase64Encoded {
+		data = protowire.AppendTag(data, 6, protowire.VarintType)
+		data = protowire.AppendVarint(data, 1)
+	}
+	if request.ParsedBody != nil {
+		parsed, err := json.Marshal(request.ParsedBody)
+		if err != nil {
+			return nil, err
+		}
+		data = appendString(data, 7, string(parsed))
+	}
+	data = appendString(data, 8, request.ParseError)
+	data = appendString(data, 9, request.Timestamp)
+	data = appendString(data, 10, request.DecodedContentEncoding)
+	return data, nil
+}
+
+// appendString appends a string field, omitting the proto3 default
+func appendString(data []byte, number protowire.Number, value string) []byte {
+	if value == "" {
+		return data
+	}
+	data = protowire.AppendTag(data, number, protowire.BytesType)
+	return protowire.AppendString(data, value)
+}
+
+// appendStringMap appends a map<string, string> field with sorted keys
+func appendStringMap(data []byte, number protowire.Number, values map[string]string) []byte {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var entry []byte
+		entry = appendString(entry, 1, key)
+		entry = appendString(entry, 2, values[key])
+		data = protowire.AppendTag(data, number, protowire.BytesType)
+		data = protowire.AppendBytes(data, entry)
+	}
+	return data
+}
+
+// decodeProtobuf decodes a protobuf body without a schema, like protoc --decode_raw
+func decodeProtobuf(data []byte) (interface{}, error) {
+	fields, err := decodeRawMessage(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid protobuf: %w", err)
+	}
+	return fields, nil
+}
+
+// decodeRawMessage decodes fields keyed by field number at nesting depth; repeated fields become arrays
+func decodeRawMessage(data []byte, depth int) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	for len(data) > 0 {
+		number, wireType, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		var value interface{}
+		switch wireType {
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			value, data = v, data[n:]
+		case protowire.Fixed32Type:
+			v, n := protowire.ConsumeFixed32(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			value, data = v, data[n:]
+		case protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			value, data = v, data[n:]
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			value, data = rawBytesValue(v, depth+1), data[n:]
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d for field %d", wireType, number)
+		}
+
+		key := strconv.Itoa(int(number))
+		switch existing := fields[key].(type) {
+		case nil:
+			fields[key] = value
+		case []interface{}:
+			fields[key] = append(existing, value)
+		default:
+			fields[key] = []interface{}{existing, value}
+		}
+	}
+	return fields, nil
+}
+
+// rawBytesValue guesses what a length-delimited field at depth holds: text, a nested message or binary
+func rawBytesValue(data []byte, depth int) interface{} {
+	if depth > maxProtobufDepth {
+		return base64.StdEncoding.EncodeToString(data)
+	}
+	// Printable text is checked first; short strings often also parse as messages
+	if isPrintable(data) {
+		return string(data)
+	}
+	if nested, err := decodeRawMessage(data, depth); err == nil {
+		return nested
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// isPrintable reports whether data is UTF-8 text without control characters
+func isPrintable(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
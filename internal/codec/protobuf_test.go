@@ -0,0 +1,123 @@
+package codec
+
+import (
+	"testing"
+
+	"echo-api/internal/models"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestMarshalProto(t *testing.T) {
+	response := &models.EchoResponse{
+		Request: models.EchoRequest{
+			Method:                 "POST",
+			Path:                   "/iot",
+			Headers:                map[string]string{"B": "2", "A": "1"},
+			QueryParams:            map[string]string{},
+			DecodedContentEncoding: "gzip",
+			ParsedBody:             map[string]interface{}{"t": 21},
+			Timestamp:              "2023-01-01T00:00:00Z",
+		},
+		Message:     "Request successfully echoed",
+		ProcessedAt: "2023-01-01T00:00:01Z",
+		Webhook:     &models.WebhookSignature{Scheme: "github", Header: "X-Hub-Signature-256"},
+	}
+
+	data, err := MarshalProto(response)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	decoded, err := decodeRawMessage(data, 0)
+	if err != nil {
+		t.Fatalf("Failed to decode protobuf: %v", err)
+	}
+
+	request := decoded["1"].(map[string]interface{})
+	if request["1"] != "POST" || request["2"] != "/iot" {
+		t.Errorf("Unexpected request fields: %#v", request)
+	}
+	headers := request["3"].([]interface{})
+	if first := headers[0].(map[string]interface{}); first["1"] != "A" || first["2"] != "1" {
+		t.Errorf("Expected sorted header entries, got %#v", headers)
+	}
+	if request["7"] != `{"t":21}` {
+		t.Errorf("Expected parsed body JSON, got %#v", request["7"])
+	}
+	if request["10"] != "gzip" {
+		t.Errorf("Expected decoded content encoding, got %#v", request["10"])
+	}
+	if decoded["2"] != "Request successfully echoed" {
+		t.Errorf("Unexpected message %#v", decoded["2"])
+	}
+	section := decoded["4"].(map[string]interface{})
+	if section["1"] != "webhook" {
+		t.Errorf("Expected webhook section, got %#v", section)
+	}
+}
+
+func TestDecodeBody_Protobuf(t *testing.T) {
+	var nested []byte
+	nested = protowire.AppendTag(nested, 1, protowire.VarintType)
+	nested = protowire.AppendVarint(nested, 7)
+
+	var data []byte
+	data = protowire.AppendTag(data, 1, protowire.BytesType)
+	data = protowire.AppendString(data, "hi")
+	data = protowire.AppendTag(data, 2, protowire.BytesType)
+	data = protowire.AppendBytes(data, nested)
+	data = protowire.AppendTag(data, 3, protowire.VarintType)
+	data = protowire.AppendVarint(data, 1)
+	data = protowire.AppendTag(data, 3, protowire.VarintType)
+	data = protowire.AppendVarint(data, 2)
+
+	parsed, err := DecodeBody("application/x-protobuf", data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fields := parsed.(map[string]interface{})
+	if fields["1"] != "hi" {
+		t.Errorf("Expected string field, got %#v", fields["1"])
+	}
+	if fields["2"].(map[string]interface{})["1"] != uint64(7) {
+		t.Errorf("Expected nested message, got %#v", fields["2"])
+	}
+	if repeated := fields["3"].([]interface{}); len(repeated) != 2 {
+		t.Errorf("Expected repeated field as array, got %#v", fields["3"])
+	}
+
+	if _, err := DecodeBody("application/x-protobuf", []byte{0x0a, 0x05}); err == nil {
+		t.Error("Expected error for truncated message")
+	}
+}
+
+func TestDecodeBody_ProtobufDepth(t *testing.T) {
+	var data []byte
+	data = protowire.AppendTag(data, 1, protowire.VarintType)
+	data = protowire.AppendVarint(data, 7)
+	for i := 0; i < maxProtobufDepth+8; i++ {
+		var wrapped []byte
+		wrapped = protowire.AppendTag(wrapped, 1, protowire.BytesType)
+		data = protowire.AppendBytes(wrapped, data)
+	}
+
+	parsed, err := DecodeBody("application/x-protobuf", data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Nested messages are decoded down to the depth limit, then kept as base64
+	value := parsed
+	for depth := 0; depth <= maxProtobufDepth; depth++ {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected a message at depth %d, got %#v", depth, value)
+		}
+		value = fields["1"]
+	}
+	if _, ok := value.(string); !ok {
+		t.Errorf("Expected base64 beyond the depth limit, got %#v", value)
+	}
+}
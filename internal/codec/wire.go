@@ -0,0 +1,51 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"unicode/utf8"
+)
+
+// Wire formats recognized by Sniff
+const (
+	FormatJSON   = "json"
+	FormatAvro   = "avro"
+	FormatAvroOC = "avro-container"
+	FormatText   = "text"
+	FormatBinary = "binary"
+)
+
+// avroContainerMagic starts an Avro object container file
+var avroContainerMagic = []byte{'O', 'b', 'j', 1}
+
+// WireFormat describes a message payload. SchemaID is set when the payload uses the schema registry
+// framing (magic byte 0 followed by a 4-byte schema id), and Payload is the data after the framing
+type WireFormat struct {
+	Format   string `json:"format"`
+	SchemaID *int   `json:"schemaId,omitempty"`
+	Payload  []byte `json:"-"`
+}
+
+// Sniff guesses the format of a message payload from its magic bytes. Framed payloads are JSON when
+// the framed data is JSON (the JSON Schema serializer) and Avro otherwise
+func Sniff(data []byte) WireFormat {
+	if len(data) >= 5 && data[0] == 0 {
+		schemaID := int(binary.BigEndian.Uint32(data[1:5]))
+		payload := data[5:]
+		if json.Valid(payload) {
+			return WireFormat{Format: FormatJSON, SchemaID: &schemaID, Payload: payload}
+		}
+		return WireFormat{Format: FormatAvro, SchemaID: &schemaID, Payload: payload}
+	}
+
+	switch {
+	case bytes.HasPrefix(data, avroContainerMagic):
+		return WireFormat{Format: FormatAvroOC, Payload: data}
+	case len(data) > 0 && json.Valid(data):
+		return WireFormat{Format: FormatJSON, Payload: data}
+	case utf8.Valid(data):
+		return WireFormat{Format: FormatText, Payload: data}
+	}
+	return WireFormat{Format: FormatBinary, Payload: data}
+}
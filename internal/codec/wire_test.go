@@ -0,0 +1,32 @@
+package codec
+
+import (
+	"testing"
+)
+
+func TestSniff(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     []byte
+		format   string
+		schemaID int
+		payload  string
+	}{
+		{"plain JSON", []byte(`{"a":1}`), FormatJSON, -1, `{"a":1}`},
+		{"registry JSON", append([]byte{0, 0, 0, 0, 7}, `{"a":1}`...), FormatJSON, 7, `{"a":1}`},
+		{"registry Avro", []byte{0, 0, 0, 1, 0, 0x06, 'f', 'o', 'o'}, FormatAvro, 256, "\x06foo"},
+		{"Avro container", []byte("Obj\x01\x04"), FormatAvroOC, -1, "Obj\x01\x04"},
+		{"text", []byte("hello"), FormatText, -1, "hello"},
+		{"binary", []byte{0xff, 0xfe}, FormatBinary, -1, "\xff\xfe"},
+	}
+
+	for _, tc := range testCases {
+		wire := Sniff(tc.data)
+		if wire.Format != tc.format || string(wire.Payload) != tc.payload {
+			t.Errorf("%s: expected %s %q, got %s %q", tc.name, tc.format, tc.payload, wire.Format, wire.Payload)
+		}
+		if (wire.SchemaID == nil) != (tc.schemaID < 0) || (wire.SchemaID != nil && *wire.SchemaID != tc.schemaID) {
+			t.Errorf("%s: expected schema id %d, got %v", tc.name, tc.schemaID, wire.SchemaID)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// Gzip is the gzip content coding
+	Gzip = "gzip"
+	// Deflate is the HTTP deflate content coding (zlib framing)
+	Deflate = "deflate"
+	// Brotli is the br content coding
+	Brotli = "br"
+	// Zstd is the zstd content coding
+	Zstd = "zstd"
+	// Identity means no content coding
+	Identity = "identity"
+
+	// MaxDecodedSize caps decompressed request bodies to guard against compression bombs
+	MaxDecodedSize = 10 << 20
+)
+
+// preference lists the supported codings, best first; ties in Accept-Encoding go to the earlier one
+var preference = []string{Brotli, Zstd, Gzip, Deflate}
+
+// Supported reports whether the coding can be produced and decoded
+func Supported(coding string) bool {
+	for _, supported := range preference {
+		if coding == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate picks the content coding for an Accept-Encoding header; it returns Identity when nothing better is acceptable
+func Negotiate(acceptEncoding string) string {
+	qualities := make(map[string]float64)
+	wildcard := -1.0
+	for _, item := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(item, ";")
+		coding := strings.ToLower(strings.TrimSpace(params[0]))
+		if coding == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil && q >= 0 && q <= 1 {
+					quality = q
+				}
+			}
+		}
+
+		if coding == "*" {
+			wildcard = quality
+		} else {
+			qualities[coding] = quality
+		}
+	}
+
+	best, bestQuality := Identity, 0.0
+	for _, coding := range preference {
+		quality, ok := qualities[coding]
+		if !ok && wildcard >= 0 {
+			quality = wildcard
+		}
+		if quality > bestQuality {
+			best, bestQuality = coding, quality
+		}
+	}
+	return best
+}
+
+// Encode compresses data with the coding
+func Encode(coding string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var writer io.WriteCloser
+	switch coding {
+	case Gzip:
+		writer = gzip.NewWriter(&buf)
+	case Deflate:
+		writer = zlib.NewWriter(&buf)
+	case Brotli:
+		writer = brotli.NewWriter(&buf)
+	case Zstd:
+		encoder, err := zstd.NewWriter(&buf)
+		if err != nil {
+			return nil, err
+		}
+		writer = encoder
+	default:
+		return nil, fmt.Errorf("unsupported content coding %q", coding)
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode reverses a Content-Encoding header value, which may list several codings in the order they were applied
+func Decode(contentEncoding string, data []byte) ([]byte, error) {
+	codings := strings.Split(contentEncoding, ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.ToLower(strings.TrimSpace(codings[i]))
+		if coding == "" || coding == Identity {
+			continue
+		}
+
+		decoded, err := decodeOne(coding, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s body: %w", coding, err)
+		}
+		data = decoded
+	}
+	return data, nil
+}
+
+// decodeOne removes a single content coding
+func decodeOne(coding string, data []byte) ([]byte, error) {
+	var reader io.Reader
+	switch coding {
+	case Gzip, "x-gzip":
+		gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	case Deflate:
+		zlibReader, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zlibReader.Close()
+		reader = zlibReader
+	case Brotli:
+		reader = brotli.NewReader(bytes.NewReader(data))
+	case Zstd:
+		decoder, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer decoder.Close()
+		reader = decoder
+	default:
+		return nil, fmt.Errorf("unsupported content coding")
+	}
+
+	decoded, err := io.ReadAll(io.LimitReader(reader, MaxDecodedSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) > MaxDecodedSize {
+		return nil, fmt.Errorf("decoded body exceeds %d bytes", MaxDecodedSize)
+	}
+	return decoded, nil
+}
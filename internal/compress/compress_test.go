@@ -0,0 +1,80 @@
+package compress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	testCases := []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{"", Identity},
+		{"gzip", Gzip},
+		{"gzip, deflate, br", Brotli},
+		{"gzip, deflate, br, zstd", Brotli},
+		{"gzip;q=1.0, br;q=0.5", Gzip},
+		{"*", Brotli},
+		{"*;q=0.5, gzip", Gzip},
+		{"br;q=0, *", Zstd},
+		{"identity", Identity},
+		{"compress", Identity},
+		{"GZIP", Gzip},
+	}
+
+	for _, tc := range testCases {
+		if result := Negotiate(tc.acceptEncoding); result != tc.expected {
+			t.Errorf("For %q, expected %s, got %s", tc.acceptEncoding, tc.expected, result)
+		}
+	}
+}
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	data := []byte(strings.Repeat(`{"message":"hello"}`, 100))
+
+	for _, coding := range []string{Gzip, Deflate, Brotli, Zstd} {
+		encoded, err := Encode(coding, data)
+		if err != nil {
+			t.Fatalf("Unexpected error encoding %s: %v", coding, err)
+		}
+		if len(encoded) >= len(data) {
+			t.Errorf("Expected %s output to be smaller than the input", coding)
+		}
+
+		decoded, err := Decode(coding, encoded)
+		if err != nil {
+			t.Fatalf("Unexpected error decoding %s: %v", coding, err)
+		}
+		if !bytes.Equal(decoded, data) {
+			t.Errorf("Expected %s round trip to return the input", coding)
+		}
+	}
+}
+
+func TestDecode_Layered(t *testing.T) {
+	data := []byte("layered body")
+	gzipped, _ := Encode(Gzip, data)
+	layered, _ := Encode(Brotli, gzipped)
+
+	decoded, err := Decode("gzip, br", layered)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(decoded, data) {
+		t.Errorf("Expected %q, got %q", data, decoded)
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	if _, err := Decode("compress", []byte("x")); err == nil {
+		t.Error("Expected error for unsupported coding")
+	}
+	if _, err := Decode(Gzip, []byte("not gzip")); err == nil {
+		t.Error("Expected error for corrupt gzip data")
+	}
+	if _, err := Encode("compress", []byte("x")); err == nil {
+		t.Error("Expected error encoding unsupported coding")
+	}
+}
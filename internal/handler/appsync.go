@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"echo-api/internal/models"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/lambda/messages"
+)
+
+// appSyncErrorArgument is the field argument that makes the resolver return a GraphQL error
+const appSyncErrorArgument = "echoError"
+
+// defaultAppSyncErrorType is used when the directive does not name an error type
+const defaultAppSyncErrorType = "EchoError"
+
+// appSyncEvent is the context AppSync passes to a direct Lambda resolver
+type appSyncEvent struct {
+	Arguments map[string]interface{} `json:"arguments"`
+	Identity  interface{}            `json:"identity"`
+	Source    interface{}            `json:"source"`
+	Request   struct {
+		Headers map[string]string `json:"headers"`
+	} `json:"request"`
+	Info struct {
+		FieldName        string                 `json:"fieldName"`
+		ParentTypeName   string                 `json:"parentTypeName"`
+		Variables        map[string]interface{} `json:"variables"`
+		SelectionSetList []string               `json:"selectionSetList"`
+	} `json:"info"`
+	Prev interface{} `json:"prev"`
+}
+
+// appSyncError is the GraphQL error requested by the echoError argument
+type appSyncError struct {
+	ErrorType    string      `json:"errorType"`
+	ErrorMessage string      `json:"errorMessage"`
+	ErrorInfo    interface{} `json:"errorInfo"`
+}
+
+// AppSyncHandler echoes AppSync direct Lambda resolver invocations
+type AppSyncHandler struct {
+	logger *logger.Logger
+}
+
+// NewAppSyncHandler creates a new AppSync resolver handler instance
+func NewAppSyncHandler() *AppSyncHandler {
+	return &AppSyncHandler{
+		logger: logger.New(),
+	}
+}
+
+// HandleRequest answers a single invocation with the echo and a batch invocation (an array of contexts)
+// with one item per context
+func (h *AppSyncHandler) HandleRequest(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		var batch []appSyncEvent
+		if err := json.Unmarshal(raw, &batch); err != nil {
+			return nil, fmt.Errorf("invalid AppSync batch: %w", err)
+		}
+		return h.handleBatch(batch), nil
+	}
+
+	var event appSyncEvent
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return nil, fmt.Errorf("invalid AppSync event: %w", err)
+	}
+
+	echo := appSyncEcho(event)
+	resolverError := appSyncErrorDirective(event.Arguments)
+	h.logger.Info("Resolving AppSync field", map[string]interface{}{
+		"field": event.Info.ParentTypeName + "." + event.Info.FieldName,
+		"echo":  echo,
+		"error": resolverError,
+	})
+
+	if resolverError != nil {
+		// AppSync reports the Lambda error type as the GraphQL errorType; errorInfo needs a batch invocation
+		return nil, messages.InvokeResponse_Error{
+			Message: resolverError.ErrorMessage,
+			Type:    resolverError.ErrorType,
+		}
+	}
+	return echo, nil
+}
+
+// handleBatch resolves every context of a batch; errors are reported per item
+func (h *AppSyncHandler) handleBatch(batch []appSyncEvent) []models.AppSyncBatchItem {
+	items := make([]models.AppSyncBatchItem, len(batch))
+	failed := 0
+	for index, event := range batch {
+		echo := appSyncEcho(event)
+		batchIndex := index
+		echo.BatchIndex = &batchIndex
+
+		if resolverError := appSyncErrorDirective(event.Arguments); resolverError != nil {
+			items[index] = models.AppSyncBatchItem{
+				ErrorMessage: resolverError.ErrorMessage,
+				ErrorType:    resolverError.ErrorType,
+				ErrorInfo:    resolverError.ErrorInfo,
+			}
+			failed++
+			continue
+		}
+		items[index] = models.AppSyncBatchItem{Data: echo}
+	}
+
+	field := ""
+	if len(batch) > 0 {
+		field = batch[0].Info.ParentTypeName + "." + batch[0].Info.FieldName
+	}
+	h.logger.Info("Resolving AppSync batch", map[string]interface{}{
+		"field":  field,
+		"size":   len(batch),
+		"failed": failed,
+		"items":  items,
+	})
+	return items
+}
+
+// appSyncEcho builds the GraphQL result for a resolver context
+func appSyncEcho(event appSyncEvent) *models.AppSyncEcho {
+	return &models.AppSyncEcho{
+		FieldName:        event.Info.FieldName,
+		ParentTypeName:   event.Info.ParentTypeName,
+		Arguments:        event.Arguments,
+		Identity:         event.Identity,
+		Source:           event.Source,
+		SelectionSetList: event.Info.SelectionSetList,
+		Headers:          event.Request.Headers,
+		Prev:             event.Prev,
+	}
+}
+
+// appSyncErrorDirective reads the echoError argument, which is either an error type or an object with
+// errorType, errorMessage and errorInfo (AWSJSON arguments arrive as strings)
+func appSyncErrorDirective(arguments map[string]interface{}) *appSyncError {
+	value, ok := arguments[appSyncErrorArgument]
+	if !ok || value == nil {
+		return nil
+	}
+
+	resolverError := &appSyncError{}
+	switch v := value.(type) {
+	case string:
+		if json.Unmarshal([]byte(v), resolverError) != nil {
+			resolverError.ErrorType = v
+		}
+	case map[string]interface{}:
+		data, _ := json.Marshal(v)
+		json.Unmarshal(data, resolverError)
+	}
+
+	if resolverError.ErrorType == "" {
+		resolverError.ErrorType = defaultAppSyncErrorType
+	}
+	if resolverError.ErrorMessage == "" {
+		resolverError.ErrorMessage = fmt.Sprintf("%s requested by the %s argument", resolverError.ErrorType, appSyncErrorArgument)
+	}
+	return resolverError
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"echo-api/internal/models"
+
+	"github.com/aws/aws-lambda-go/lambda/messages"
+)
+
+const appSyncEventJSON = `{
+	"arguments": {"id": "1"},
+	"identity": {"sub": "abc", "username": "alice"},
+	"source": {"postId": "p1"},
+	"request": {"headers": {"x-trace": "t1"}, "domainName": null},
+	"info": {"fieldName": "comment", "parentTypeName": "Post", "selectionSetList": ["id", "body"], "variables": {}},
+	"prev": {"result": {"step": 1}},
+	"stash": {}
+}`
+
+func TestAppSyncHandler_Single(t *testing.T) {
+	h := NewAppSyncHandler()
+
+	result, err := h.HandleRequest(context.Background(), json.RawMessage(appSyncEventJSON))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	echo := result.(*models.AppSyncEcho)
+	if echo.FieldName != "comment" || echo.ParentTypeName != "Post" || echo.Arguments["id"] != "1" {
+		t.Errorf("Unexpected echo %+v", echo)
+	}
+	if len(echo.SelectionSetList) != 2 || echo.Headers["x-trace"] != "t1" || echo.BatchIndex != nil {
+		t.Errorf("Unexpected echo %+v", echo)
+	}
+	if echo.Source.(map[string]interface{})["postId"] != "p1" || echo.Prev == nil || echo.Identity == nil {
+		t.Errorf("Expected source, prev and identity to be echoed, got %+v", echo)
+	}
+}
+
+func TestAppSyncHandler_SingleError(t *testing.T) {
+	h := NewAppSyncHandler()
+
+	event := `{"arguments": {"echoError": "{\"errorType\": \"NotFound\", \"errorMessage\": \"no such post\"}"}, "info": {"fieldName": "post"}}`
+	_, err := h.HandleRequest(context.Background(), json.RawMessage(event))
+
+	invokeError, ok := err.(messages.InvokeResponse_Error)
+	if !ok {
+		t.Fatalf("Expected an invoke error, got %v", err)
+	}
+	if invokeError.Type != "NotFound" || invokeError.Message != "no such post" {
+		t.Errorf("Unexpected error %+v", invokeError)
+	}
+}
+
+func TestAppSyncHandler_Batch(t *testing.T) {
+	h := NewAppSyncHandler()
+
+	batch := `[` + appSyncEventJSON + `, {"arguments": {"echoError": {"errorType": "Forbidden", "errorInfo": {"reason": "test"}}}, "info": {"fieldName": "comment"}}]`
+	result, err := h.HandleRequest(context.Background(), json.RawMessage(batch))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	items := result.([]models.AppSyncBatchItem)
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(items))
+	}
+	if echo, ok := items[0].Data.(*models.AppSyncEcho); !ok || *echo.BatchIndex != 0 || items[0].ErrorType != "" {
+		t.Errorf("Expected the first item to resolve, got %+v", items[0])
+	}
+	if items[1].Data != nil || items[1].ErrorType != "Forbidden" || items[1].ErrorMessage == "" {
+		t.Errorf("Expected the second item to fail, got %+v", items[1])
+	}
+	if info, ok := items[1].ErrorInfo.(map[string]interface{}); !ok || info["reason"] != "test" {
+		t.Errorf("Expected errorInfo, got %v", items[1].ErrorInfo)
+	}
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"echo-api/internal/authorizer"
+	"echo-api/internal/models"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// defaultPrincipalID identifies callers when the matching rule names no principal
+const defaultPrincipalID = "echo"
+
+// AuthorizerHandler answers API Gateway custom authorizer requests from configured rules and passes
+// the authorizer input on in the context
+type AuthorizerHandler struct {
+	logger *logger.Logger
+	config *authorizer.Config
+}
+
+// NewAuthorizerHandler creates a new authorizer handler; missing or invalid rules deny every request
+func NewAuthorizerHandler() *AuthorizerHandler {
+	l := logger.New()
+	config, err := authorizer.ConfigFromEnv()
+	if err != nil {
+		l.Warn("Failed to load authorizer rules, denying all requests", map[string]interface{}{
+			"error": err.Error(),
+		})
+		config = &authorizer.Config{DefaultEffect: authorizer.EffectDeny}
+	} else if len(config.Rules) == 0 {
+		l.Warn("No authorizer rules configured, applying the default effect to every request", map[string]interface{}{
+			"defaultEffect": config.DefaultEffect,
+		})
+	}
+	return NewAuthorizerHandlerWithConfig(config)
+}
+
+// NewAuthorizerHandlerWithConfig creates a new authorizer handler that evaluates config
+func NewAuthorizerHandlerWithConfig(config *authorizer.Config) *AuthorizerHandler {
+	return &AuthorizerHandler{
+		logger: logger.New(),
+		config: config,
+	}
+}
+
+// HandleToken answers a TOKEN authorizer request, which only carries the token
+func (h *AuthorizerHandler) HandleToken(ctx context.Context, request events.APIGatewayCustomAuthorizerRequest) (events.APIGatewayCustomAuthorizerResponse, error) {
+	decision := h.config.Evaluate(authorizer.Input{Token: request.AuthorizationToken})
+	return h.respond(request.Type, request.MethodArn, decision, request)
+}
+
+// HandleRequest answers a REQUEST authorizer request; the token is read from the Authorization header
+func (h *AuthorizerHandler) HandleRequest(ctx context.Context, request events.APIGatewayCustomAuthorizerRequestTypeRequest) (events.APIGatewayCustomAuthorizerResponse, error) {
+	token, _ := models.HeaderValue(request.Headers, "Authorization")
+	decision := h.config.Evaluate(authorizer.Input{
+		Token:    token,
+		Headers:  request.Headers,
+		SourceIP: request.RequestContext.Identity.SourceIP,
+	})
+	return h.respond(request.Type, request.MethodArn, decision, request)
+}
+
+// respond builds the policy for decision; the Unauthorized effect becomes the error API Gateway maps to 401
+func (h *AuthorizerHandler) respond(authorizerType, methodArn string, decision authorizer.Decision, input interface{}) (events.APIGatewayCustomAuthorizerResponse, error) {
+	h.logger.Info("Processing authorizer request", map[string]interface{}{
+		"type":       authorizerType,
+		"method_arn": methodArn,
+		"effect":     decision.Effect,
+		"rule":       decision.Rule,
+		"input":      input,
+	})
+
+	if decision.Effect == authorizer.EffectUnauthorized {
+		return events.APIGatewayCustomAuthorizerResponse{}, errors.New("Unauthorized")
+	}
+
+	principalID := decision.PrincipalID
+	if principalID == "" {
+		principalID = defaultPrincipalID
+	}
+
+	// Context values must be strings, numbers or booleans, so the input travels as JSON
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return events.APIGatewayCustomAuthorizerResponse{}, err
+	}
+
+	return events.APIGatewayCustomAuthorizerResponse{
+		PrincipalID: principalID,
+		PolicyDocument: events.APIGatewayCustomAuthorizerPolicy{
+			Version: "2012-10-17",
+			Statement: []events.IAMPolicyStatement{{
+				Action:   []string{"execute-api:Invoke"},
+				Effect:   decision.Effect,
+				Resource: []string{stageResource(methodArn)},
+			}},
+		},
+		Context: map[string]interface{}{
+			"authorizerType": authorizerType,
+			"effect":         decision.Effect,
+			"rule":           decision.Rule,
+			"input":          string(inputJSON),
+		},
+	}, nil
+}
+
+// stageResource widens a method ARN to every method and path of its stage, so a cached policy
+// applies to the whole API
+func stageResource(methodArn string) string {
+	parts := strings.SplitN(methodArn, "/", 3)
+	if len(parts) < 2 {
+		return methodArn
+	}
+	return parts[0] + "/" + parts[1] + "/*"
+}
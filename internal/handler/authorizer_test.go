@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"echo-api/internal/authorizer"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const methodArn = "arn:aws:execute-api:us-east-1:123456789012:abcdef123/prod/GET/echo/items"
+
+func testAuthorizerHandler(t *testing.T) *AuthorizerHandler {
+	config, err := authorizer.ParseConfig([]byte(`{"rules": [
+		{"name": "office", "principalId": "office", "sourceCidrs": ["10.0.0.0/8"]},
+		{"name": "token", "principalId": "alice", "tokenPattern": "^Bearer good$"},
+		{"name": "no-token", "effect": "Unauthorized", "tokenPattern": "^$"}
+	]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return NewAuthorizerHandlerWithConfig(config)
+}
+
+func TestAuthorizerHandler_HandleToken(t *testing.T) {
+	h := testAuthorizerHandler(t)
+
+	resp, err := h.HandleToken(context.Background(), events.APIGatewayCustomAuthorizerRequest{
+		Type:               "TOKEN",
+		AuthorizationToken: "Bearer good",
+		MethodArn:          methodArn,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.PrincipalID != "alice" {
+		t.Errorf("Expected principal alice, got %s", resp.PrincipalID)
+	}
+	statement := resp.PolicyDocument.Statement[0]
+	if statement.Effect != authorizer.EffectAllow {
+		t.Errorf("Expected Allow, got %s", statement.Effect)
+	}
+	if statement.Resource[0] != "arn:aws:execute-api:us-east-1:123456789012:abcdef123/prod/*" {
+		t.Errorf("Expected the stage wildcard resource, got %s", statement.Resource[0])
+	}
+
+	// The full input is passed on as a JSON string
+	var input events.APIGatewayCustomAuthorizerRequest
+	if err := json.Unmarshal([]byte(resp.Context["input"].(string)), &input); err != nil {
+		t.Fatalf("Expected JSON input in context: %v", err)
+	}
+	if input.AuthorizationToken != "Bearer good" || resp.Context["rule"] != "token" {
+		t.Errorf("Unexpected context %v", resp.Context)
+	}
+
+	resp, err = h.HandleToken(context.Background(), events.APIGatewayCustomAuthorizerRequest{
+		Type:               "TOKEN",
+		AuthorizationToken: "Bearer bad",
+		MethodArn:          methodArn,
+	})
+	if err != nil || resp.PolicyDocument.Statement[0].Effect != authorizer.EffectDeny || resp.PrincipalID != defaultPrincipalID {
+		t.Errorf("Expected Deny for %s, got %+v (%v)", defaultPrincipalID, resp, err)
+	}
+
+	if _, err := h.HandleToken(context.Background(), events.APIGatewayCustomAuthorizerRequest{MethodArn: methodArn}); err == nil || err.Error() != "Unauthorized" {
+		t.Errorf("Expected Unauthorized error, got %v", err)
+	}
+}
+
+func TestAuthorizerHandler_HandleRequest(t *testing.T) {
+	h := testAuthorizerHandler(t)
+
+	request := events.APIGatewayCustomAuthorizerRequestTypeRequest{
+		Type:      "REQUEST",
+		MethodArn: methodArn,
+		Headers:   map[string]string{"authorization": "Bearer good"},
+	}
+	request.RequestContext.Identity.SourceIP = "10.1.2.3"
+
+	resp, err := h.HandleRequest(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.PrincipalID != "office" || resp.Context["rule"] != "office" {
+		t.Errorf("Expected the source IP rule to match, got %+v", resp)
+	}
+
+	// The token is read from the Authorization header
+	request.RequestContext.Identity.SourceIP = "192.0.2.1"
+	resp, err = h.HandleRequest(context.Background(), request)
+	if err != nil || resp.PrincipalID != "alice" {
+		t.Errorf("Expected the token rule to match, got %+v (%v)", resp, err)
+	}
+}
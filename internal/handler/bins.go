@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/internal/recorder"
+	"echo-api/internal/stream"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// minBinPollInterval bounds how often a bin stream reads the store
+const minBinPollInterval = time.Second
+
+// Path prefixes of the bin endpoints; they carry bin tokens and stay out of the shared history
+const (
+	binPrefix  = "/b/"
+	binsPrefix = "/bins"
+)
+
+// handleBinCreate creates a bin named ?name=, expiring after ?ttl= and keeping up to ?max= requests,
+// both bounded by the configured limits; it only accepts POST
+func (h *LambdaHandler) handleBinCreate(ctx context.Context, r *routeRequest) (events.APIGatewayProxyResponse, error) {
+	if h.bins == nil {
+		return h.binsDisabled()
+	}
+	if r.proxy.HTTPMethod != http.MethodPost {
+		return h.createErrorResponse(http.StatusMethodNotAllowed, "Method Not Allowed", "Use POST to create a bin")
+	}
+
+	query := r.echo.QueryParams
+	ttl := h.binLimits.TTL
+	if value := query["ttl"]; value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 || parsed > h.binLimits.TTL {
+			return h.createErrorResponse(http.StatusBadRequest, "Bad Request", fmt.Sprintf("ttl must be a duration such as 30m, up to %v", h.binLimits.TTL))
+		}
+		ttl = parsed
+	}
+	maxRequests := h.binLimits.MaxRequests
+	if value := query["max"]; value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > h.binLimits.MaxRequests {
+			return h.createErrorResponse(http.StatusBadRequest, "Bad Request", fmt.Sprintf("max must be an integer between 1 and %d", h.binLimits.MaxRequests))
+		}
+		maxRequests = parsed
+	}
+
+	bin := recorder.NewBin(query["name"], h.now(), ttl, maxRequests)
+	err := h.bins.CreateBin(ctx, bin)
+	if errors.Is(err, recorder.ErrTooManyBins) {
+		return h.createErrorResponse(http.StatusServiceUnavailable, "Service Unavailable", "Too many active bins; try again after some expire")
+	}
+	if err != nil {
+		return h.binStoreError(err)
+	}
+
+	h.logger.Info("Bin created", map[string]interface{}{
+		"name":      bin.Name,
+		"expiresAt": bin.ExpiresAt,
+	})
+	return h.historyResponse(http.StatusCreated, &models.BinResponse{Bin: bin, CapturePath: bin.CapturePath()})
+}
+
+// handleBin returns a bin with its requests, filtered like the history
+func (h *LambdaHandler) handleBin(ctx context.Context, r *routeRequest) (events.APIGatewayProxyResponse, error) {
+	if h.bins == nil {
+		return h.binsDisabled()
+	}
+
+	filter, message := historyFilter(r.proxy.QueryStringParameters)
+	if message != "" {
+		return h.createErrorResponse(http.StatusBadRequest, "Bad Request", message)
+	}
+
+	token := r.params["token"]
+	bin, err := h.bins.GetBin(ctx, token)
+	if err != nil {
+		return h.binLookupError(err)
+	}
+	records, err := h.bins.ListRecords(ctx, token, filter)
+	if err != nil {
+		return h.binLookupError(err)
+	}
+	return h.historyResponse(http.StatusOK, &models.BinResponse{
+		Bin:         bin,
+		CapturePath: bin.CapturePath(),
+		Records:     records,
+		Count:       len(records),
+	})
+}
+
+// handleBinCapture records a request to /b/{token}/... into the bin and echoes it
+func (h *LambdaHandler) handleBinCapture(ctx context.Context, r *routeRequest) (events.APIGatewayProxyResponse, error) {
+	if h.bins == nil {
+		return h.binsDisabled()
+	}
+
+	record := recorder.NewRecord(r.echo, h.now())
+	if err := h.bins.PutRecord(ctx, r.params["token"], record); err != nil {
+		return h.binLookupError(err)
+	}
+
+	response, err := h.handleEcho(ctx, r)
+	if err == nil && response.Headers != nil {
+		response.Headers["X-Echo-Bin-Record"] = record.ID
+	}
+	return response, err
+}
+
+// handleBinStream sends a bin's requests as server-sent events, oldest first, then polls every
+// ?interval= seconds for new ones until the stream duration limit; Last-Event-ID resumes after a record
+func (h *LambdaHandler) handleBinStream(ctx context.Context, r *routeRequest) *stream.Response {
+	if h.bins == nil {
+		response, _ := h.binsDisabled()
+		return bufferedStream(response)
+	}
+	interval, err := streamSeconds(r.echo.QueryParams["interval"], time.Second)
+	if err != nil || interval < minBinPollInterval {
+		return h.errorStream(http.StatusBadRequest, "Bad Request", fmt.Sprintf("interval must be at least %v", minBinPollInterval))
+	}
+
+	token := r.params["token"]
+	if _, err := h.bins.GetBin(ctx, token); err != nil {
+		response, _ := h.binLookupError(err)
+		return bufferedStream(response)
+	}
+
+	// Record ids sort by time, so the last id seen marks where to continue
+	lastID := r.echo.Header("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.echo.QueryParams["lastEventId"]
+	}
+
+	h.logger.Info("Streaming bin", map[string]interface{}{
+		"lastEventId": lastID,
+	})
+
+	return &stream.Response{
+		StatusCode: http.StatusOK,
+		Headers:    responseHeaders("text/event-stream", "Cache-Control", "no-cache", "X-Accel-Buffering", "no"),
+		Body: func(ctx context.Context, w io.Writer) error {
+			deadline := time.Now().Add(maxStreamDuration)
+			for {
+				records, err := h.bins.ListRecords(ctx, token, recorder.Filter{})
+				if errors.Is(err, recorder.ErrBinNotFound) {
+					// The bin expired while streaming
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+
+				for i := len(records) - 1; i >= 0; i-- {
+					if records[i].ID <= lastID {
+						continue
+					}
+					data, err := json.Marshal(records[i])
+					if err != nil {
+						return err
+					}
+					if _, err := (stream.Event{ID: records[i].ID, Name: "request", Data: string(data)}).WriteTo(w); err != nil {
+						return err
+					}
+					lastID = records[i].ID
+				}
+
+				if !time.Now().Add(interval).Before(deadline) {
+					return nil
+				}
+				if err := stream.Sleep(ctx, interval); err != nil {
+					return err
+				}
+			}
+		},
+	}
+}
+
+// isBinPath reports whether path is a bin capture or bin endpoint, either of which names a bin token
+func isBinPath(path string) bool {
+	return strings.HasPrefix(path, binPrefix) || path == binsPrefix || strings.HasPrefix(path, binsPrefix+"/")
+}
+
+// binsDisabled answers the bin endpoints when the bin store could not be configured
+func (h *LambdaHandler) binsDisabled() (events.APIGatewayProxyResponse, error) {
+	return h.createErrorResponse(http.StatusNotFound, "Not Found", "Bins are disabled; check the BIN_* settings")
+}
+
+// binLookupError reports a missing or expired bin as 404 and any other failure as a store error
+func (h *LambdaHandler) binLookupError(err error) (events.APIGatewayProxyResponse, error) {
+	if errors.Is(err, recorder.ErrBinNotFound) {
+		return h.createErrorResponse(http.StatusNotFound, "Not Found", "The bin does not exist or has expired")
+	}
+	return h.binStoreError(err)
+}
+
+// binStoreError reports a bin store failure
+func (h *LambdaHandler) binStoreError(err error) (events.APIGatewayProxyResponse, error) {
+	h.logger.Error("Bin store failed", map[string]interface{}{
+		"error": err.Error(),
+	})
+	return h.createErrorResponse(http.StatusBadGateway, "Bad Gateway", "The bin store is unavailable")
+}
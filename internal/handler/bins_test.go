@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/internal/recorder"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestHandleRequest_Bins(t *testing.T) {
+	handler := NewLambdaHandler()
+	handler.recorder = recorder.NewMemoryStore(10)
+	handler.bins = recorder.NewMemoryBinStore(10)
+	handler.binLimits = recorder.BinLimits{TTL: time.Hour, MaxRequests: 5}
+	ctx := context.Background()
+
+	send := func(method, path string, query map[string]string) events.APIGatewayProxyResponse {
+		response, err := handler.HandleRequest(ctx, events.APIGatewayProxyRequest{
+			HTTPMethod:            method,
+			Path:                  path,
+			QueryStringParameters: query,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return response
+	}
+
+	var created models.BinResponse
+	response := send("POST", "/bins", map[string]string{"name": "team-a", "max": "2"})
+	json.Unmarshal([]byte(response.Body), &created)
+	if response.StatusCode != http.StatusCreated || created.Name != "team-a" || created.MaxRequests != 2 || created.CapturePath != "/b/"+created.Token {
+		t.Fatalf("Expected a new bin, got %d %s", response.StatusCode, response.Body)
+	}
+
+	response = send("POST", created.CapturePath+"/hooks/github", nil)
+	if response.StatusCode != http.StatusOK || response.Headers["X-Echo-Bin-Record"] == "" {
+		t.Errorf("Expected the capture to be echoed with its record id, got %d %v", response.StatusCode, response.Headers)
+	}
+	send("GET", created.CapturePath, nil)
+	send("GET", "/status", nil)
+
+	var bin models.BinResponse
+	json.Unmarshal([]byte(send("GET", "/bins/"+created.Token, nil).Body), &bin)
+	if bin.Count != 2 || bin.Records[1].Request.Path != created.CapturePath+"/hooks/github" {
+		t.Errorf("Expected both captures newest first, got %+v", bin)
+	}
+	json.Unmarshal([]byte(send("GET", "/bins/"+created.Token, map[string]string{"method": "POST"}).Body), &bin)
+	if bin.Count != 1 {
+		t.Errorf("Expected the POST capture, got %d records", bin.Count)
+	}
+
+	// Captures and bin endpoints stay out of the shared history, so it never reveals a token
+	var history models.HistoryResponse
+	json.Unmarshal([]byte(send("GET", "/_history", map[string]string{"path": "/b/*"}).Body), &history)
+	if history.Count != 0 {
+		t.Errorf("Expected no captures in the history, got %d", history.Count)
+	}
+	if body := send("GET", "/_history", nil).Body; strings.Contains(body, created.Token) {
+		t.Errorf("Expected the history not to contain the bin token, got %s", body)
+	}
+
+	if response := send("GET", "/b/missing/hooks", nil); response.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown bin, got %d", response.StatusCode)
+	}
+	if response := send("GET", "/bins", nil); response.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", response.StatusCode)
+	}
+	if response := send("POST", "/bins", map[string]string{"ttl": "48h"}); response.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a TTL beyond the limit, got %d", response.StatusCode)
+	}
+}
+
+func TestHandleBinStream(t *testing.T) {
+	handler := NewLambdaHandler()
+	handler.bins = recorder.NewMemoryBinStore(10)
+	ctx := context.Background()
+
+	bin := recorder.NewBin("", time.Now(), time.Hour, 10)
+	handler.bins.CreateBin(ctx, bin)
+	first := recorder.NewRecord(models.NewEchoRequest("GET", "/b/"+bin.Token+"/first", map[string]string{}, map[string]string{}, ""), time.Now())
+	second := recorder.NewRecord(models.NewEchoRequest("GET", "/b/"+bin.Token+"/second", map[string]string{}, map[string]string{}, ""), time.Now().Add(time.Millisecond))
+	handler.bins.PutRecord(ctx, bin.Token, first)
+	handler.bins.PutRecord(ctx, bin.Token, second)
+
+	request := &routeRequest{
+		echo:   models.NewEchoRequest("GET", "/bins/"+bin.Token+"/stream", map[string]string{"Last-Event-ID": first.ID}, map[string]string{}, ""),
+		params: map[string]string{"token": bin.Token},
+	}
+	response := handler.handleBinStream(ctx, request)
+	if response.StatusCode != http.StatusOK || response.Headers["Content-Type"] != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %d %v", response.StatusCode, response.Headers)
+	}
+
+	// Stop the stream after a few polls
+	streamCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	var body bytes.Buffer
+	response.Body(streamCtx, &body)
+
+	if !strings.HasPrefix(body.String(), "id: "+second.ID+"\nevent: request\n") || strings.Count(body.String(), "id: ") != 1 {
+		t.Errorf("Expected only the record after Last-Event-ID, got %q", body.String())
+	}
+
+	tooFast := handler.handleBinStream(ctx, &routeRequest{
+		echo:   models.NewEchoRequest("GET", "/bins/"+bin.Token+"/stream", map[string]string{}, map[string]string{"interval": "0.01"}, ""),
+		params: map[string]string{"token": bin.Token},
+	})
+	if tooFast.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an interval below the minimum, got %d", tooFast.StatusCode)
+	}
+
+	missing := handler.handleBinStream(ctx, &routeRequest{echo: request.echo, params: map[string]string{"token": "missing"}})
+	if missing.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown bin, got %d", missing.StatusCode)
+	}
+}
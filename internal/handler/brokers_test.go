@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestKafkaEcho(t *testing.T) {
+	var event events.KafkaEvent
+	json.Unmarshal([]byte(`{
+		"eventSource": "aws:kafka", "eventSourceArn": "arn:aws:kafka:us-east-1:123456789012:cluster/demo/abc",
+		"records": {"orders-0": [{
+			"topic": "orders", "partition": 0, "offset": 15, "timestamp": 1545084650987, "timestampType": "CREATE_TIME",
+			"key": "`+base64.StdEncoding.EncodeToString([]byte("order-1"))+`",
+			"value": "`+base64.StdEncoding.EncodeToString(append([]byte{0, 0, 0, 0, 42}, `{"total":10}`...))+`",
+			"headers": [{"traceparent": [48, 48]}, {"bin": [-1, -2]}]
+		}]}
+	}`), &event)
+
+	echo := kafkaEcho(event, event.Records["orders-0"][0])
+	if echo.ID != "orders-0@15" || echo.Attributes["key"] != "order-1" {
+		t.Errorf("Unexpected echo %+v", echo)
+	}
+	if echo.Attributes["format"] != "json" || echo.Attributes["schemaId"] != 42 {
+		t.Errorf("Expected schema registry JSON, got %v", echo.Attributes)
+	}
+	if detail, ok := echo.Detail.(map[string]interface{}); !ok || detail["total"] == nil {
+		t.Errorf("Expected the framed JSON to be parsed, got %v", echo.Detail)
+	}
+
+	headers := echo.Attributes["headers"].([]map[string]string)
+	if headers[0]["traceparent"] != "00" || headers[1]["bin"] != "//4=" {
+		t.Errorf("Expected headers as strings, got %v", headers)
+	}
+}
+
+func TestActiveMQEcho(t *testing.T) {
+	message := events.ActiveMQMessage{
+		MessageID:   "ID:b-1",
+		MessageType: "jms/text-message",
+		Timestamp:   1598827811958,
+		Destination: events.ActiveMQDestination{PhysicalName: "testQueue"},
+		Data:        base64.StdEncoding.EncodeToString([]byte("Obj\x01avro")),
+	}
+
+	echo := activeMQEcho(events.ActiveMQEvent{EventSource: "aws:mq"}, message)
+	if echo.Attributes["format"] != "avro-container" || echo.Attributes["isBase64Encoded"] != true {
+		t.Errorf("Expected an Avro container, got %v", echo.Attributes)
+	}
+	if echo.Time != "2020-08-30T22:50:11.958Z" || echo.Attributes["destination"] != "testQueue" {
+		t.Errorf("Unexpected echo %+v", echo)
+	}
+}
+
+func TestRabbitMQEcho(t *testing.T) {
+	var event events.RabbitMQEvent
+	json.Unmarshal([]byte(`{
+		"eventSource": "aws:rmq",
+		"rmqMessagesByQueue": {"pizzaQueue::/": [{
+			"basicProperties": {"contentType": "text/plain", "headers": {"header1": {"bytes": [118, 97, 108, 117, 101, 49]}, "numberInHeader": 10}, "deliveryMode": 1},
+			"redelivered": false,
+			"data": "`+base64.StdEncoding.EncodeToString([]byte("hello"))+`"
+		}]}
+	}`), &event)
+
+	echo := rabbitMQEcho(event, "pizzaQueue::/", event.MessagesByQueue["pizzaQueue::/"][0])
+	headers := echo.Attributes["headers"].(map[string]interface{})
+	if headers["header1"] != "value1" || headers["numberInHeader"] != float64(10) {
+		t.Errorf("Expected byte headers as strings, got %v", headers)
+	}
+	if echo.Body != "hello" || echo.Attributes["format"] != "text" {
+		t.Errorf("Unexpected echo %+v", echo)
+	}
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"echo-api/internal/cache"
+	"echo-api/internal/models"
+	"echo-api/internal/render"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// protectedResponseHeaders cannot be set through /response-headers because API Gateway or the encoder owns them
+var protectedResponseHeaders = map[string]bool{
+	"content-length":    true,
+	"content-encoding":  true,
+	"transfer-encoding": true,
+	"connection":        true,
+}
+
+// handleCache serves /cache and /cache/{seconds} with validators computed over the echo body;
+// ?strong=1 serves the stable echo itself so that its bytes, and a strong ETag over them, repeat
+func (h *LambdaHandler) handleCache(ctx context.Context, r *routeRequest) (events.APIGatewayProxyResponse, error) {
+	cacheControl := "no-cache"
+	if value, ok := r.params["seconds"]; ok {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			return h.createErrorResponse(http.StatusBadRequest, "Bad Request", "seconds must be a non-negative integer")
+		}
+		cacheControl = "public, max-age=" + strconv.Itoa(seconds)
+	}
+
+	format, ok := h.negotiateFormat(r)
+	if !ok {
+		return h.createErrorResponse(http.StatusNotAcceptable, "Not Acceptable", "Supported formats: "+render.Supported())
+	}
+
+	var echoResponse *models.EchoResponse
+	strong := r.echo.QueryParams["strong"] == "1"
+	if strong {
+		echoResponse = stableEcho(r)
+	}
+	etag, err := h.echoETag(r, format, strong)
+	if err != nil {
+		return h.createErrorResponse(http.StatusInternalServerError, "Internal Server Error", "Failed to compute ETag")
+	}
+
+	// The resource is treated as modified at the start of the current UTC day
+	resource := cache.Resource{
+		ETag:         etag,
+		LastModified: h.now().UTC().Truncate(24 * time.Hour),
+	}
+	return h.conditionalEcho(r, format, resource, cacheControl, echoResponse)
+}
+
+// handleETag serves /etag/{etag} as a resource whose current ETag is the path value
+func (h *LambdaHandler) handleETag(ctx context.Context, r *routeRequest) (events.APIGatewayProxyResponse, error) {
+	format, ok := h.negotiateFormat(r)
+	if !ok {
+		return h.createErrorResponse(http.StatusNotAcceptable, "Not Acceptable", "Supported formats: "+render.Supported())
+	}
+	return h.conditionalEcho(r, format, cache.Resource{ETag: cache.ParseETag(r.params["etag"])}, "", nil)
+}
+
+// handleResponseHeaders echoes the request and sets every query parameter as a response header
+func (h *LambdaHandler) handleResponseHeaders(ctx context.Context, r *routeRequest) (events.APIGatewayProxyResponse, error) {
+	response, err := h.handleEcho(ctx, r)
+	if err != nil || response.StatusCode != http.StatusOK {
+		return response, err
+	}
+
+	for name, value := range r.echo.QueryParams {
+		if name == "format" || protectedResponseHeaders[strings.ToLower(name)] {
+			continue
+		}
+		response.Headers[http.CanonicalHeaderKey(name)] = value
+	}
+	return response, nil
+}
+
+// conditionalEcho evaluates conditional headers against resource and serves echoResponse (the request echo when nil),
+// a 304 or a 412
+func (h *LambdaHandler) conditionalEcho(r *routeRequest, format *render.Format, resource cache.Resource, cacheControl string, echoResponse *models.EchoResponse) (events.APIGatewayProxyResponse, error) {
+	validators := map[string]string{"ETag": resource.ETag.String()}
+	if !resource.LastModified.IsZero() {
+		validators["Last-Modified"] = resource.LastModified.Format(http.TimeFormat)
+	}
+	if cacheControl != "" {
+		validators["Cache-Control"] = cacheControl
+	}
+
+	var response events.APIGatewayProxyResponse
+	var err error
+	switch status := cache.Evaluate(r.proxy.HTTPMethod, r.echo.Header, resource); status {
+	case http.StatusNotModified:
+		h.logger.Info("Conditional request not modified", map[string]interface{}{
+			"path": r.proxy.Path,
+			"etag": validators["ETag"],
+		})
+		// Match the Vary of the 200: only weakly tagged responses are negotiated with Accept-Encoding
+		headers := responseHeaders(format.ContentType, "Vary", "Accept")
+		if resource.ETag.Weak {
+			addVary(headers, "Accept-Encoding")
+		}
+		response = events.APIGatewayProxyResponse{
+			StatusCode: http.StatusNotModified,
+			Headers:    headers,
+		}
+	case http.StatusPreconditionFailed:
+		response, err = h.createErrorResponse(http.StatusPreconditionFailed, "Precondition Failed", "The resource does not match the request preconditions")
+	default:
+		if echoResponse == nil {
+			echoResponse = h.newEchoResponse(r)
+		}
+		response, err = h.renderEcho(r, format, echoResponse)
+	}
+
+	for name, value := range validators {
+		response.Headers[name] = value
+	}
+	return response, err
+}
+
+// stableEcho echoes the request with headers and timestamps left out, so repeated requests for the same
+// path, query and body render the same bytes
+func stableEcho(r *routeRequest) *models.EchoResponse {
+	stable := *r.echo
+	stable.Headers = nil
+	stable.Timestamp = ""
+
+	echoResponse := models.NewEchoResponse(&stable, "Request successfully echoed")
+	echoResponse.ProcessedAt = ""
+	return echoResponse
+}
+
+// echoETag computes an ETag over the identity rendering of the stable echo; it is weak unless strong is set,
+// because the full echo sent by default carries headers and timestamps that differ on every request
+func (h *LambdaHandler) echoETag(r *routeRequest, format *render.Format, strong bool) (cache.ETag, error) {
+	body, err := format.Render(stableEcho(r))
+	if err != nil {
+		return cache.ETag{}, err
+	}
+	return cache.Compute([]byte(body), !strong), nil
+}
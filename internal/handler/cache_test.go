@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestHandleRequest_Cache(t *testing.T) {
+	handler := NewLambdaHandler()
+	handler.now = func() time.Time { return time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC) }
+	ctx := context.Background()
+
+	first, err := handler.HandleRequest(ctx, events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: "/cache/60"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", first.StatusCode)
+	}
+	if first.Headers["Cache-Control"] != "public, max-age=60" {
+		t.Errorf("Expected max-age cache control, got %q", first.Headers["Cache-Control"])
+	}
+	if first.Headers["Last-Modified"] != "Tue, 02 Jan 2024 00:00:00 GMT" {
+		t.Errorf("Unexpected Last-Modified %q", first.Headers["Last-Modified"])
+	}
+	etag := first.Headers["ETag"]
+	if etag == "" {
+		t.Fatal("Expected ETag header")
+	}
+
+	// Revalidating with different request headers still hits the same tag
+	second, _ := handler.HandleRequest(ctx, events.APIGatewayProxyRequest{
+		HTTPMethod: "GET",
+		Path:       "/cache/60",
+		Headers:    map[string]string{"If-None-Match": etag, "X-Amzn-Trace-Id": "Root=1-abc"},
+	})
+	if second.StatusCode != http.StatusNotModified || second.Body != "" {
+		t.Errorf("Expected empty 304, got %d with %q", second.StatusCode, second.Body)
+	}
+	if second.Headers["ETag"] != etag || second.Headers["Vary"] == "" {
+		t.Errorf("Expected 304 to carry ETag and Vary, got %v", second.Headers)
+	}
+
+	// The tag is weak, so compressed and identity responses may share it
+	if etag[:2] != "W/" {
+		t.Errorf("Expected a weak ETag, got %q", etag)
+	}
+	compressed, _ := handler.HandleRequest(ctx, events.APIGatewayProxyRequest{
+		HTTPMethod: "GET",
+		Path:       "/cache/60",
+		Headers:    map[string]string{"Accept-Encoding": "gzip"},
+	})
+	if compressed.Headers["ETag"] != etag {
+		t.Errorf("Expected the same weak ETag, got %v", compressed.Headers)
+	}
+
+	// A strong ETag is never shared between encodings, so the response is not compressed
+	handler.minCompressSize = 0
+	strong, _ := handler.HandleRequest(ctx, events.APIGatewayProxyRequest{
+		HTTPMethod: "GET",
+		Path:       "/etag/v1",
+		Headers:    map[string]string{"Accept-Encoding": "gzip"},
+	})
+	if strong.Headers["Content-Encoding"] != "" || strong.Headers["ETag"] != `"v1"` {
+		t.Errorf("Expected an uncompressed response with a strong ETag, got %v", strong.Headers)
+	}
+
+	// The 304 varies on the same headers as the 200 it revalidates
+	for _, path := range []string{"/cache/60", "/etag/v1"} {
+		full, _ := handler.HandleRequest(ctx, events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: path})
+		revalidated, _ := handler.HandleRequest(ctx, events.APIGatewayProxyRequest{
+			HTTPMethod: "GET",
+			Path:       path,
+			Headers:    map[string]string{"If-None-Match": full.Headers["ETag"]},
+		})
+		if revalidated.StatusCode != http.StatusNotModified || revalidated.Headers["Vary"] != full.Headers["Vary"] {
+			t.Errorf("For %s, expected 304 with Vary %q, got %d with %q", path, full.Headers["Vary"], revalidated.StatusCode, revalidated.Headers["Vary"])
+		}
+	}
+
+	invalid, _ := handler.HandleRequest(ctx, events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: "/cache/soon"})
+	if invalid.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid seconds, got %d", invalid.StatusCode)
+	}
+}
+
+func TestHandleRequest_CacheStrong(t *testing.T) {
+	handler := NewLambdaHandler()
+	handler.minCompressSize = 0
+	ctx := context.Background()
+
+	for _, format := range []string{"json", "yaml", "xml", "msgpack", "cbor", "protobuf"} {
+		request := events.APIGatewayProxyRequest{
+			HTTPMethod:            "GET",
+			Path:                  "/cache",
+			Headers:               map[string]string{"Accept-Encoding": "gzip", "X-Request-Id": "1"},
+			QueryStringParameters: map[string]string{"strong": "1", "format": format, "a": "1", "b": "2"},
+		}
+		first, err := handler.HandleRequest(ctx, request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		request.Headers = map[string]string{"Accept-Encoding": "gzip", "X-Request-Id": "2"}
+		second, _ := handler.HandleRequest(ctx, request)
+
+		// Strong validators require byte-identical bodies, so the identity encoding is always sent
+		etag := first.Headers["ETag"]
+		if etag == "" || etag[:2] == "W/" {
+			t.Errorf("For %s, expected a strong ETag, got %q", format, etag)
+		}
+		if first.Headers["Content-Encoding"] != "" {
+			t.Errorf("For %s, expected an uncompressed body, got %q", format, first.Headers["Content-Encoding"])
+		}
+		if second.Headers["ETag"] != etag || second.Body != first.Body {
+			t.Errorf("For %s, expected repeated requests to share body and ETag, got %q and %q", format, etag, second.Headers["ETag"])
+		}
+	}
+}
+
+func TestHandleRequest_ETag(t *testing.T) {
+	handler := NewLambdaHandler()
+	ctx := context.Background()
+
+	testCases := []struct {
+		headers  map[string]string
+		expected int
+	}{
+		{map[string]string{}, http.StatusOK},
+		{map[string]string{"If-None-Match": `"v1"`}, http.StatusNotModified},
+		{map[string]string{"If-Match": `"v2"`}, http.StatusPreconditionFailed},
+		{map[string]string{"If-Match": `"v1"`}, http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		response, err := handler.HandleRequest(ctx, events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: "/etag/v1", Headers: tc.headers})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.StatusCode != tc.expected {
+			t.Errorf("For %v, expected %d, got %d", tc.headers, tc.expected, response.StatusCode)
+		}
+		if response.Headers["ETag"] != `"v1"` {
+			t.Errorf("Expected ETag \"v1\", got %q", response.Headers["ETag"])
+		}
+	}
+}
+
+func TestHandleRequest_ResponseHeaders(t *testing.T) {
+	handler := NewLambdaHandler()
+
+	response, err := handler.HandleRequest(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod: "GET",
+		Path:       "/response-headers",
+		QueryStringParameters: map[string]string{
+			"cache-control":    "max-age=30",
+			"content-encoding": "gzip",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Headers["Cache-Control"] != "max-age=30" {
+		t.Errorf("Expected Cache-Control from query, got %q", response.Headers["Cache-Control"])
+	}
+	if response.Headers["Content-Encoding"] != "" {
+		t.Errorf("Expected Content-Encoding to be protected, got %q", response.Headers["Content-Encoding"])
+	}
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"echo-api/internal/cloudfront"
+	"echo-api/internal/models"
+	"echo-api/pkg/logger"
+)
+
+// edgeRespondDirective asks a request trigger to answer with the echo instead of forwarding the request;
+// it is read from the header or the query string because Lambda@Edge has no environment variables
+const edgeRespondDirective = "x-echo-respond"
+
+// Generated responses are limited by the trigger type
+const (
+	maxViewerResponseBody = 40 * 1024
+	maxOriginResponseBody = 1024 * 1024
+)
+
+// CloudFrontHandler echoes Lambda@Edge viewer and origin events
+type CloudFrontHandler struct {
+	logger *logger.Logger
+}
+
+// NewCloudFrontHandler creates a new CloudFront handler instance
+func NewCloudFrontHandler() *CloudFrontHandler {
+	return &CloudFrontHandler{
+		logger: logger.New(),
+	}
+}
+
+// HandleRequest returns the request or response with x-echo-edge-* headers injected; request triggers
+// answer with the echo when the x-echo-respond directive is set
+func (h *CloudFrontHandler) HandleRequest(ctx context.Context, event cloudfront.Event) (interface{}, error) {
+	if len(event.Records) == 0 || event.Records[0].CF.Request == nil {
+		return nil, errors.New("CloudFront event has no request")
+	}
+	cf := event.Records[0].CF
+	echo := edgeEcho(cf)
+
+	respond := cloudfront.IsRequestEvent(cf.Config.EventType) && edgeRespondRequested(cf.Request)
+	h.logger.Info("Processing CloudFront event", map[string]interface{}{
+		"echo":     echo,
+		"response": cf.Response,
+		"respond":  respond,
+	})
+
+	switch {
+	case respond:
+		return edgeResponse(echo)
+	case cf.Response != nil:
+		if cf.Response.Headers == nil {
+			cf.Response.Headers = cloudfront.Headers{}
+		}
+		injectEdgeHeaders(cf.Response.Headers, cf.Config)
+		return cf.Response, nil
+	default:
+		if cf.Request.Headers == nil {
+			cf.Request.Headers = cloudfront.Headers{}
+		}
+		injectEdgeHeaders(cf.Request.Headers, cf.Config)
+		return cf.Request, nil
+	}
+}
+
+// edgeEcho describes the request of a CloudFront event
+func edgeEcho(cf cloudfront.CF) *models.EdgeEcho {
+	echo := models.NewEdgeEcho(cf.Config.EventType)
+	echo.DistributionID = cf.Config.DistributionID
+	echo.DistributionDomainName = cf.Config.DistributionDomainName
+	echo.RequestID = cf.Config.RequestID
+	echo.ClientIP = cf.Request.ClientIP
+	echo.Method = cf.Request.Method
+	echo.URI = cf.Request.URI
+	echo.Querystring = cf.Request.Querystring
+	echo.Headers = cf.Request.Headers
+	if len(cf.Request.Origin) > 0 {
+		echo.Origin = cf.Request.Origin
+	}
+	if cf.Request.Body != nil {
+		echo.Body = cf.Request.Body
+	}
+	return echo
+}
+
+// edgeRespondRequested reports whether the header or query string carries the respond directive
+func edgeRespondRequested(request *cloudfront.Request) bool {
+	if isTrue(request.Headers.Get(edgeRespondDirective)) {
+		return true
+	}
+	query, err := url.ParseQuery(request.Querystring)
+	return err == nil && isTrue(query.Get(edgeRespondDirective))
+}
+
+// edgeResponse generates a JSON response with the echo; when it exceeds the trigger's limit the body, headers
+// and origin are left out in turn, and an echo that still does not fit is answered with a short 413
+func edgeResponse(echo *models.EdgeEcho) (*cloudfront.Response, error) {
+	limit := maxOriginResponseBody
+	if echo.EventType == cloudfront.ViewerRequest {
+		limit = maxViewerResponseBody
+	}
+
+	body, err := json.Marshal(echo)
+	if err != nil {
+		return nil, err
+	}
+	trims := []struct {
+		name string
+		drop func()
+	}{
+		{"body", func() { echo.Body = nil }},
+		{"headers", func() { echo.Headers = nil }},
+		{"origin", func() { echo.Origin = nil }},
+	}
+	for _, trim := range trims {
+		if len(body) <= limit {
+			break
+		}
+		trim.drop()
+		echo.Omitted = append(echo.Omitted, trim.name)
+		if body, err = json.Marshal(echo); err != nil {
+			return nil, err
+		}
+	}
+	if len(body) > limit {
+		body, err = json.Marshal(models.NewErrorResponse("Payload Too Large", fmt.Sprintf("The echo exceeds the %d byte limit of %s responses", limit, echo.EventType)))
+		if err != nil {
+			return nil, err
+		}
+		return edgeJSONResponse("413", "Payload Too Large", body), nil
+	}
+	return edgeJSONResponse("200", "OK", body), nil
+}
+
+// edgeJSONResponse wraps a JSON body in a generated response that CloudFront must not cache
+func edgeJSONResponse(status, description string, body []byte) *cloudfront.Response {
+	headers := cloudfront.Headers{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set("Cache-Control", "no-store")
+	return &cloudfront.Response{
+		Status:            status,
+		StatusDescription: description,
+		Headers:           headers,
+		Body:              string(body),
+		BodyEncoding:      "text",
+	}
+}
+
+// injectEdgeHeaders marks a request or response with the trigger that handled it
+func injectEdgeHeaders(headers cloudfront.Headers, config cloudfront.Config) {
+	headers.Set("X-Echo-Edge-Event", config.EventType)
+	headers.Set("X-Echo-Edge-Request-Id", config.RequestID)
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"echo-api/internal/cloudfront"
+	"echo-api/internal/models"
+)
+
+// cloudFrontEvent builds an event for eventType with the given query string and optional response
+func cloudFrontEvent(t *testing.T, eventType, querystring, response string) cloudfront.Event {
+	data := `{"Records": [{"cf": {
+		"config": {"distributionDomainName": "d111111abcdef8.cloudfront.net", "distributionId": "EDFDVBD6EXAMPLE", "eventType": "` + eventType + `", "requestId": "req-1"},
+		"request": {
+			"clientIp": "203.0.113.178", "method": "GET", "uri": "/index.html", "querystring": "` + querystring + `",
+			"headers": {"host": [{"key": "Host", "value": "d111111abcdef8.cloudfront.net"}]},
+			"origin": {"s3": {"domainName": "bucket.s3.amazonaws.com", "path": ""}}
+		}` + response + `
+	}}]}`
+
+	var event cloudfront.Event
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatalf("Failed to unmarshal event: %v", err)
+	}
+	return event
+}
+
+func TestCloudFrontHandler_PassThroughRequest(t *testing.T) {
+	h := NewCloudFrontHandler()
+
+	result, err := h.HandleRequest(context.Background(), cloudFrontEvent(t, cloudfront.OriginRequest, "a=1", ""))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	request := result.(*cloudfront.Request)
+	if request.Headers.Get("X-Echo-Edge-Event") != cloudfront.OriginRequest || request.Headers.Get("Host") == "" {
+		t.Errorf("Expected the edge headers to be injected, got %v", request.Headers)
+	}
+	if request.URI != "/index.html" || len(request.Origin) == 0 {
+		t.Errorf("Expected the request to pass through, got %+v", request)
+	}
+}
+
+func TestCloudFrontHandler_GeneratedResponse(t *testing.T) {
+	h := NewCloudFrontHandler()
+
+	result, err := h.HandleRequest(context.Background(), cloudFrontEvent(t, cloudfront.ViewerRequest, "x-echo-respond=true", ""))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	response := result.(*cloudfront.Response)
+	if response.Status != "200" || response.Headers.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected response %+v", response)
+	}
+
+	var echo models.EdgeEcho
+	if err := json.Unmarshal([]byte(response.Body), &echo); err != nil {
+		t.Fatalf("Failed to unmarshal echo: %v", err)
+	}
+	if echo.EventType != cloudfront.ViewerRequest || echo.ClientIP != "203.0.113.178" || echo.Querystring != "x-echo-respond=true" {
+		t.Errorf("Unexpected echo %+v", echo)
+	}
+}
+
+func TestCloudFrontHandler_GeneratedResponseLimit(t *testing.T) {
+	h := NewCloudFrontHandler()
+
+	// Headers alone exceed the viewer limit, so they are left out after the (absent) body
+	event := cloudFrontEvent(t, cloudfront.ViewerRequest, "x-echo-respond=true", "")
+	event.Records[0].CF.Request.Headers.Set("X-Large", strings.Repeat("h", maxViewerResponseBody))
+	result, err := h.HandleRequest(context.Background(), event)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	response := result.(*cloudfront.Response)
+	if response.Status != "200" || len(response.Body) > maxViewerResponseBody {
+		t.Fatalf("Expected a 200 within the viewer limit, got %s with %d bytes", response.Status, len(response.Body))
+	}
+	var echo models.EdgeEcho
+	if err := json.Unmarshal([]byte(response.Body), &echo); err != nil {
+		t.Fatalf("Failed to unmarshal echo: %v", err)
+	}
+	if echo.Headers != nil || strings.Join(echo.Omitted, ",") != "body,headers" {
+		t.Errorf("Expected headers to be omitted, got %+v", echo)
+	}
+
+	// An echo that does not fit even without headers is answered with a short error
+	event = cloudFrontEvent(t, cloudfront.ViewerRequest, "x-echo-respond=true", "")
+	event.Records[0].CF.Request.URI = "/" + strings.Repeat("u", maxViewerResponseBody)
+	result, err = h.HandleRequest(context.Background(), event)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	response = result.(*cloudfront.Response)
+	if response.Status != "413" || len(response.Body) > maxViewerResponseBody {
+		t.Errorf("Expected a short 413, got %s with %d bytes", response.Status, len(response.Body))
+	}
+}
+
+func TestCloudFrontHandler_Response(t *testing.T) {
+	h := NewCloudFrontHandler()
+
+	// Only request triggers answer with the echo; response triggers pass the response on
+	event := cloudFrontEvent(t, cloudfront.OriginResponse, "x-echo-respond=true",
+		`, "response": {"status": "404", "statusDescription": "Not Found", "headers": {}}`)
+	result, err := h.HandleRequest(context.Background(), event)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	response := result.(*cloudfront.Response)
+	if response.Status != "404" || response.Headers.Get("X-Echo-Edge-Request-Id") != "req-1" {
+		t.Errorf("Expected the origin response with edge headers, got %+v", response)
+	}
+}
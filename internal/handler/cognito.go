@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// customChallenge is the challenge name Cognito uses for Lambda-defined challenges
+const customChallenge = "CUSTOM_CHALLENGE"
+
+// maxChallengeAttempts is how many wrong answers the custom auth flow accepts before failing
+const maxChallengeAttempts = 3
+
+// CognitoConfig lists the modifications the trigger applies; the zero value returns every event as received
+type CognitoConfig struct {
+	AutoConfirm     bool
+	AutoVerifyEmail bool
+	AutoVerifyPhone bool
+	AddClaims       map[string]string
+	SuppressClaims  []string
+	EmailSubject    string
+	EmailMessage    string
+	SMSMessage      string
+	ChallengeAnswer string
+}
+
+// CognitoConfigFromEnv reads the modifications from COGNITO_* environment variables
+func CognitoConfigFromEnv() (CognitoConfig, error) {
+	config := CognitoConfig{
+		AutoConfirm:     isTrue(os.Getenv("COGNITO_AUTO_CONFIRM")),
+		AutoVerifyEmail: isTrue(os.Getenv("COGNITO_AUTO_VERIFY_EMAIL")),
+		AutoVerifyPhone: isTrue(os.Getenv("COGNITO_AUTO_VERIFY_PHONE")),
+		EmailSubject:    os.Getenv("COGNITO_EMAIL_SUBJECT"),
+		EmailMessage:    os.Getenv("COGNITO_EMAIL_MESSAGE"),
+		SMSMessage:      os.Getenv("COGNITO_SMS_MESSAGE"),
+		ChallengeAnswer: os.Getenv("COGNITO_CHALLENGE_ANSWER"),
+	}
+	if value := os.Getenv("COGNITO_ADD_CLAIMS"); value != "" {
+		if err := json.Unmarshal([]byte(value), &config.AddClaims); err != nil {
+			return config, fmt.Errorf("invalid COGNITO_ADD_CLAIMS: %w", err)
+		}
+	}
+	for _, claim := range strings.Split(os.Getenv("COGNITO_SUPPRESS_CLAIMS"), ",") {
+		if claim = strings.TrimSpace(claim); claim != "" {
+			config.SuppressClaims = append(config.SuppressClaims, claim)
+		}
+	}
+	return config, nil
+}
+
+// CognitoHandler is a passthrough Cognito User Pool trigger: it logs every event and returns it
+// with the configured modifications
+type CognitoHandler struct {
+	logger *logger.Logger
+	config CognitoConfig
+}
+
+// NewCognitoHandler creates a new Cognito trigger handler; invalid configuration leaves events unchanged
+func NewCognitoHandler() *CognitoHandler {
+	config, err := CognitoConfigFromEnv()
+	if err != nil {
+		logger.New().Warn("Ignoring invalid Cognito configuration", map[string]interface{}{
+			"error": err.Error(),
+		})
+		config = CognitoConfig{}
+	}
+	return NewCognitoHandlerWithConfig(config)
+}
+
+// NewCognitoHandlerWithConfig creates a new Cognito trigger handler that applies config
+func NewCognitoHandlerWithConfig(config CognitoConfig) *CognitoHandler {
+	return &CognitoHandler{
+		logger: logger.New(),
+		config: config,
+	}
+}
+
+// HandleRequest dispatches on the trigger source; unknown triggers and events it does not modify are returned as received
+func (h *CognitoHandler) HandleRequest(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var header events.CognitoEventUserPoolsHeader
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, fmt.Errorf("invalid Cognito event: %w", err)
+	}
+
+	// apply reports whether it modified the event; unmodified events are returned as received,
+	// keeping fields the typed events do not model
+	var event interface{}
+	var apply func() bool
+	switch source := header.TriggerSource; {
+	case strings.HasPrefix(source, "PreSignUp_"):
+		e := &events.CognitoEventUserPoolsPreSignup{}
+		event, apply = e, func() bool { return h.preSignup(e) }
+	case strings.HasPrefix(source, "TokenGeneration_") && header.Version == "1":
+		// Version 2 events carry access token overrides the typed event does not model
+		e := &events.CognitoEventUserPoolsPreTokenGen{}
+		event, apply = e, func() bool { return h.preTokenGen(e) }
+	case strings.HasPrefix(source, "PostConfirmation_"):
+		event, apply = &events.CognitoEventUserPoolsPostConfirmation{}, func() bool { return false }
+	case strings.HasPrefix(source, "CustomMessage_"):
+		e := &events.CognitoEventUserPoolsCustomMessage{}
+		event, apply = e, func() bool { return h.customMessage(e) }
+	case strings.HasPrefix(source, "DefineAuthChallenge_"):
+		e := &events.CognitoEventUserPoolsDefineAuthChallenge{}
+		event, apply = e, func() bool { return h.defineAuthChallenge(e) }
+	case strings.HasPrefix(source, "CreateAuthChallenge_"):
+		e := &events.CognitoEventUserPoolsCreateAuthChallenge{}
+		event, apply = e, func() bool { return h.createAuthChallenge(e) }
+	case strings.HasPrefix(source, "VerifyAuthChallengeResponse_"):
+		e := &events.CognitoEventUserPoolsVerifyAuthChallenge{}
+		event, apply = e, func() bool { return h.verifyAuthChallenge(e) }
+	}
+
+	if event == nil {
+		h.logger.Info("Passing through Cognito trigger", map[string]interface{}{
+			"trigger_source": header.TriggerSource,
+			"event":          redactPassword(raw),
+		})
+		return raw, nil
+	}
+
+	if err := json.Unmarshal(raw, event); err != nil {
+		return nil, fmt.Errorf("invalid %s event: %w", header.TriggerSource, err)
+	}
+	modified := apply()
+
+	h.logger.Info("Processed Cognito trigger", map[string]interface{}{
+		"trigger_source": header.TriggerSource,
+		"user_pool_id":   header.UserPoolID,
+		"user_name":      header.UserName,
+		"modified":       modified,
+		"event":          event,
+	})
+	if !modified {
+		return raw, nil
+	}
+	return event, nil
+}
+
+// preSignup confirms and verifies the user when configured; only attributes that were supplied can be verified
+func (h *CognitoHandler) preSignup(event *events.CognitoEventUserPoolsPreSignup) bool {
+	modified := false
+	if h.config.AutoConfirm {
+		event.Response.AutoConfirmUser = true
+		modified = true
+	}
+	if h.config.AutoVerifyEmail && event.Request.UserAttributes["email"] != "" {
+		event.Response.AutoVerifyEmail = true
+		modified = true
+	}
+	if h.config.AutoVerifyPhone && event.Request.UserAttributes["phone_number"] != "" {
+		event.Response.AutoVerifyPhone = true
+		modified = true
+	}
+	return modified
+}
+
+// preTokenGen adds and suppresses the configured claims
+func (h *CognitoHandler) preTokenGen(event *events.CognitoEventUserPoolsPreTokenGen) bool {
+	if len(h.config.AddClaims) == 0 && len(h.config.SuppressClaims) == 0 {
+		return false
+	}
+	details := &event.Response.ClaimsOverrideDetails
+	if len(h.config.AddClaims) > 0 {
+		if details.ClaimsToAddOrOverride == nil {
+			details.ClaimsToAddOrOverride = make(map[string]string, len(h.config.AddClaims))
+		}
+		for name, value := range h.config.AddClaims {
+			details.ClaimsToAddOrOverride[name] = value
+		}
+	}
+	details.ClaimsToSuppress = append(details.ClaimsToSuppress, h.config.SuppressClaims...)
+	return true
+}
+
+// customMessage replaces the messages Cognito sends; configured messages must contain the code placeholder {####}
+func (h *CognitoHandler) customMessage(event *events.CognitoEventUserPoolsCustomMessage) bool {
+	if h.config.EmailSubject == "" && h.config.EmailMessage == "" && h.config.SMSMessage == "" {
+		return false
+	}
+	if h.config.EmailSubject != "" {
+		event.Response.EmailSubject = h.config.EmailSubject
+	}
+	if h.config.EmailMessage != "" {
+		event.Response.EmailMessage = h.config.EmailMessage
+	}
+	if h.config.SMSMessage != "" {
+		event.Response.SMSMessage = h.config.SMSMessage
+	}
+	return true
+}
+
+// defineAuthChallenge drives a custom auth flow that asks for the configured answer until it is given
+// or maxChallengeAttempts answers were wrong
+func (h *CognitoHandler) defineAuthChallenge(event *events.CognitoEventUserPoolsDefineAuthChallenge) bool {
+	if h.config.ChallengeAnswer == "" {
+		return false
+	}
+
+	session := event.Request.Session
+	switch {
+	case event.Request.UserNotFound:
+		event.Response.FailAuthentication = true
+	case len(session) > 0 && session[len(session)-1].ChallengeName == customChallenge && session[len(session)-1].ChallengeResult:
+		event.Response.IssueTokens = true
+	case len(session) >= maxChallengeAttempts:
+		event.Response.FailAuthentication = true
+	default:
+		event.Response.ChallengeName = customChallenge
+	}
+	return true
+}
+
+// createAuthChallenge stores the configured answer where only verifyAuthChallenge can see it
+func (h *CognitoHandler) createAuthChallenge(event *events.CognitoEventUserPoolsCreateAuthChallenge) bool {
+	if h.config.ChallengeAnswer == "" || event.Request.ChallengeName != customChallenge {
+		return false
+	}
+	event.Response.PublicChallengeParameters = map[string]string{"challenge": "echo"}
+	event.Response.PrivateChallengeParameters = map[string]string{"answer": h.config.ChallengeAnswer}
+	event.Response.ChallengeMetadata = "ECHO_CHALLENGE"
+	return true
+}
+
+// verifyAuthChallenge compares the user's answer with the one createAuthChallenge stored
+func (h *CognitoHandler) verifyAuthChallenge(event *events.CognitoEventUserPoolsVerifyAuthChallenge) bool {
+	expected := event.Request.PrivateChallengeParameters["answer"]
+	if h.config.ChallengeAnswer == "" || expected == "" {
+		return false
+	}
+	answer := fmt.Sprint(event.Request.ChallengeAnswer)
+	event.Response.AnswerCorrect = subtle.ConstantTimeCompare([]byte(answer), []byte(expected)) == 1
+	return true
+}
+
+// redactPassword hides the password user migration triggers receive, so it never reaches the logs
+func redactPassword(raw json.RawMessage) interface{} {
+	var event map[string]interface{}
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return raw
+	}
+	if request, ok := event["request"].(map[string]interface{}); ok {
+		if _, ok := request["password"]; ok {
+			request["password"] = "[REDACTED]"
+		}
+	}
+	return event
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// cognitoEvent builds a raw trigger event with the given trigger source, request and response
+func cognitoEvent(triggerSource, request, response string) json.RawMessage {
+	return json.RawMessage(`{"version": "1", "triggerSource": "` + triggerSource + `", "region": "us-east-1",
+		"userPoolId": "us-east-1_abc", "userName": "alice",
+		"callerContext": {"awsSdkVersion": "aws-sdk-unknown-unknown", "clientId": "client"},
+		"request": ` + request + `, "response": ` + response + `}`)
+}
+
+func TestCognitoHandler_PreSignup(t *testing.T) {
+	h := NewCognitoHandlerWithConfig(CognitoConfig{AutoConfirm: true, AutoVerifyEmail: true, AutoVerifyPhone: true})
+
+	result, err := h.HandleRequest(context.Background(), cognitoEvent("PreSignUp_SignUp",
+		`{"userAttributes": {"email": "alice@example.com"}}`, `{}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	event := result.(*events.CognitoEventUserPoolsPreSignup)
+	if !event.Response.AutoConfirmUser || !event.Response.AutoVerifyEmail {
+		t.Errorf("Expected the user to be confirmed and verified, got %+v", event.Response)
+	}
+	// Cognito rejects verifying an attribute that was not supplied
+	if event.Response.AutoVerifyPhone {
+		t.Error("Expected the phone number not to be verified")
+	}
+	if event.UserName != "alice" || event.Request.UserAttributes["email"] != "alice@example.com" {
+		t.Errorf("Expected the event to be returned, got %+v", event)
+	}
+}
+
+func TestCognitoHandler_PreTokenGen(t *testing.T) {
+	h := NewCognitoHandlerWithConfig(CognitoConfig{
+		AddClaims:      map[string]string{"tenant": "acme"},
+		SuppressClaims: []string{"email"},
+	})
+
+	result, err := h.HandleRequest(context.Background(), cognitoEvent("TokenGeneration_Authentication",
+		`{"userAttributes": {"sub": "1"}, "groupConfiguration": {"groupsToOverride": ["admins"]}}`, `{"claimsOverrideDetails": null}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	details := result.(*events.CognitoEventUserPoolsPreTokenGen).Response.ClaimsOverrideDetails
+	if details.ClaimsToAddOrOverride["tenant"] != "acme" {
+		t.Errorf("Expected the tenant claim, got %v", details.ClaimsToAddOrOverride)
+	}
+	if len(details.ClaimsToSuppress) != 1 || details.ClaimsToSuppress[0] != "email" {
+		t.Errorf("Expected email to be suppressed, got %v", details.ClaimsToSuppress)
+	}
+}
+
+func TestCognitoHandler_Passthrough(t *testing.T) {
+	h := NewCognitoHandlerWithConfig(CognitoConfig{})
+
+	// Unknown triggers are returned byte for byte
+	raw := cognitoEvent("UserMigration_Authentication", `{"password": "secret"}`, `{}`)
+	result, err := h.HandleRequest(context.Background(), raw)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(result.(json.RawMessage)) != string(raw) {
+		t.Errorf("Expected the raw event, got %s", result)
+	}
+
+	// Without configuration known triggers are returned as received too, including fields
+	// the typed event does not model
+	raw = cognitoEvent("CustomMessage_SignUp", `{"codeParameter": "{####}", "linkParameter": "{##Click##}"}`, `{}`)
+	result, _ = h.HandleRequest(context.Background(), raw)
+	if string(result.(json.RawMessage)) != string(raw) {
+		t.Errorf("Expected the raw custom message event, got %s", result)
+	}
+}
+
+func TestCognitoHandler_CustomAuthFlow(t *testing.T) {
+	h := NewCognitoHandlerWithConfig(CognitoConfig{ChallengeAnswer: "42"})
+	ctx := context.Background()
+
+	result, _ := h.HandleRequest(ctx, cognitoEvent("DefineAuthChallenge_Authentication", `{"session": []}`, `{}`))
+	if name := result.(*events.CognitoEventUserPoolsDefineAuthChallenge).Response.ChallengeName; name != customChallenge {
+		t.Errorf("Expected %s, got %q", customChallenge, name)
+	}
+
+	result, _ = h.HandleRequest(ctx, cognitoEvent("CreateAuthChallenge_Authentication", `{"challengeName": "CUSTOM_CHALLENGE"}`, `{}`))
+	private := result.(*events.CognitoEventUserPoolsCreateAuthChallenge).Response.PrivateChallengeParameters
+	if private["answer"] != "42" {
+		t.Errorf("Expected the answer in the private parameters, got %v", private)
+	}
+
+	for answer, expected := range map[string]bool{`"42"`: true, `"41"`: false} {
+		result, _ = h.HandleRequest(ctx, cognitoEvent("VerifyAuthChallengeResponse_Authentication",
+			`{"privateChallengeParameters": {"answer": "42"}, "challengeAnswer": `+answer+`}`, `{}`))
+		if correct := result.(*events.CognitoEventUserPoolsVerifyAuthChallenge).Response.AnswerCorrect; correct != expected {
+			t.Errorf("Answer %s: expected %v, got %v", answer, expected, correct)
+		}
+	}
+
+	session := `{"challengeName": "CUSTOM_CHALLENGE", "challengeResult": true}`
+	result, _ = h.HandleRequest(ctx, cognitoEvent("DefineAuthChallenge_Authentication", `{"session": [`+session+`]}`, `{}`))
+	if !result.(*events.CognitoEventUserPoolsDefineAuthChallenge).Response.IssueTokens {
+		t.Error("Expected tokens to be issued after a correct answer")
+	}
+
+	wrong := `{"challengeName": "CUSTOM_CHALLENGE", "challengeResult": false}`
+	result, _ = h.HandleRequest(ctx, cognitoEvent("DefineAuthChallenge_Authentication", `{"session": [`+wrong+`,`+wrong+`,`+wrong+`]}`, `{}`))
+	if !result.(*events.CognitoEventUserPoolsDefineAuthChallenge).Response.FailAuthentication {
+		t.Error("Expected authentication to fail after three wrong answers")
+	}
+}
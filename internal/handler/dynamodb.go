@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"echo-api/internal/codec"
+	"echo-api/internal/models"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// DynamoDBHandler echoes DynamoDB Streams records as plain JSON images with a diff
+type DynamoDBHandler struct {
+	logger *logger.Logger
+}
+
+// NewDynamoDBHandler creates a new DynamoDB Streams handler instance
+func NewDynamoDBHandler() *DynamoDBHandler {
+	return &DynamoDBHandler{
+		logger: logger.New(),
+	}
+}
+
+// HandleRequest logs every record and reports records whose new image has "echo-fail": true as batch
+// item failures; stream records do not name their shard, so the summary is grouped by stream
+func (h *DynamoDBHandler) HandleRequest(ctx context.Context, event events.DynamoDBEvent) (events.DynamoDBEventResponse, error) {
+	response := events.DynamoDBEventResponse{BatchItemFailures: []events.DynamoDBBatchItemFailure{}}
+	streams := make(shardSummaries)
+
+	for _, record := range event.Records {
+		echo := dynamoDBEcho(record)
+		detail, _ := echo.Detail.(map[string]interface{})
+		failed := failRequested(detail["newImage"])
+
+		h.logger.Info("Received DynamoDB stream record", map[string]interface{}{
+			"record": echo,
+			"failed": failed,
+		})
+
+		streams.add(record.EventSourceArn, record.Change.SequenceNumber, failed)
+		if failed {
+			response.BatchItemFailures = append(response.BatchItemFailures, events.DynamoDBBatchItemFailure{ItemIdentifier: record.Change.SequenceNumber})
+		}
+	}
+
+	h.logger.Info("Processed DynamoDB stream batch", map[string]interface{}{
+		"records":  len(event.Records),
+		"failures": len(response.BatchItemFailures),
+		"streams":  streams,
+	})
+	return response, nil
+}
+
+// dynamoDBEcho normalizes a stream record; the detail holds the keys, both images and their diff
+func dynamoDBEcho(record events.DynamoDBEventRecord) *models.EventEcho {
+	echo := models.NewEventEcho(record.EventSource)
+	echo.DetailType = record.EventName
+	echo.ID = record.EventID
+	echo.Time = record.Change.ApproximateCreationDateTime.UTC().Format(time.RFC3339)
+	echo.Resources = []string{record.EventSourceArn}
+	echo.Attributes["awsRegion"] = record.AWSRegion
+	echo.Attributes["sequenceNumber"] = record.Change.SequenceNumber
+	echo.Attributes["sizeBytes"] = record.Change.SizeBytes
+	echo.Attributes["streamViewType"] = record.Change.StreamViewType
+	if record.UserIdentity != nil {
+		// Set for deletions made by TTL
+		echo.Attributes["userIdentity"] = record.UserIdentity
+	}
+
+	oldImage := codec.FromImage(record.Change.OldImage)
+	newImage := codec.FromImage(record.Change.NewImage)
+	detail := map[string]interface{}{
+		"keys": codec.FromImage(record.Change.Keys),
+	}
+	if oldImage != nil {
+		detail["oldImage"] = oldImage
+	}
+	if newImage != nil {
+		detail["newImage"] = newImage
+	}
+	if oldImage != nil && newImage != nil {
+		detail["diff"] = diffImages(oldImage, newImage)
+	}
+	echo.Detail = detail
+	return echo
+}
+
+// imageChange is a changed attribute in an image diff
+type imageChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// imageDiff lists the attributes added, removed and changed between two images
+type imageDiff struct {
+	Added   map[string]interface{} `json:"added,omitempty"`
+	Removed map[string]interface{} `json:"removed,omitempty"`
+	Changed map[string]imageChange `json:"changed,omitempty"`
+}
+
+// diffImages compares the top-level attributes of two images
+func diffImages(oldImage, newImage map[string]interface{}) imageDiff {
+	diff := imageDiff{
+		Added:   make(map[string]interface{}),
+		Removed: make(map[string]interface{}),
+		Changed: make(map[string]imageChange),
+	}
+	for name, oldValue := range oldImage {
+		newValue, ok := newImage[name]
+		if !ok {
+			diff.Removed[name] = oldValue
+		} else if !reflect.DeepEqual(oldValue, newValue) {
+			diff.Changed[name] = imageChange{Old: oldValue, New: newValue}
+		}
+	}
+	for name, newValue := range newImage {
+		if _, ok := oldImage[name]; !ok {
+			diff.Added[name] = newValue
+		}
+	}
+	return diff
+}
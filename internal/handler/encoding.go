@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"echo-api/internal/compress"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// defaultCompressMinSize is the smallest body compressed through Accept-Encoding negotiation
+const defaultCompressMinSize = 1024
+
+// compressionMinSize reads COMPRESSION_MIN_SIZE, falling back to the default
+func compressionMinSize(l *logger.Logger) int {
+	value := os.Getenv("COMPRESSION_MIN_SIZE")
+	if value == "" {
+		return defaultCompressMinSize
+	}
+
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 0 {
+		l.Warn("Invalid COMPRESSION_MIN_SIZE", map[string]interface{}{
+			"value": value,
+		})
+		return defaultCompressMinSize
+	}
+	return size
+}
+
+// compressedEcho echoes the request and always compresses the result with coding
+func compressedEcho(coding string) routeHandler {
+	return func(h *LambdaHandler, ctx context.Context, r *routeRequest) (events.APIGatewayProxyResponse, error) {
+		response, err := h.handleEcho(ctx, r)
+		if err != nil || response.StatusCode != http.StatusOK {
+			return response, err
+		}
+		return h.compressResponse(response, coding), nil
+	}
+}
+
+// negotiateEncoding compresses the response with the best coding the client accepts, once it reaches the minimum size;
+// ranged resources are left alone because Content-Range refers to the unencoded bytes, and so is a response
+// with a strong ETag, which would otherwise validate two different encodings
+func (h *LambdaHandler) negotiateEncoding(response events.APIGatewayProxyResponse, acceptEncoding string) events.APIGatewayProxyResponse {
+	if response.Headers == nil || response.Headers["Content-Encoding"] != "" || response.Body == "" || response.StatusCode == http.StatusPartialContent {
+		return response
+	}
+	// A resumed download joins the full response with later 206 parts, so both must be identity
+	if response.Headers["Accept-Ranges"] != "" {
+		return response
+	}
+	if etag := response.Headers["ETag"]; etag != "" && !strings.HasPrefix(etag, "W/") {
+		return response
+	}
+	addVary(response.Headers, "Accept-Encoding")
+
+	coding := compress.Negotiate(acceptEncoding)
+	if coding == compress.Identity || len(responseBytes(response)) < h.minCompressSize {
+		return response
+	}
+	return h.compressResponse(response, coding)
+}
+
+// compressResponse encodes the body with coding; API Gateway needs the result base64 encoded
+func (h *LambdaHandler) compressResponse(response events.APIGatewayProxyResponse, coding string) events.APIGatewayProxyResponse {
+	compressed, err := compress.Encode(coding, responseBytes(response))
+	if err != nil {
+		h.logger.Error("Failed to compress response", map[string]interface{}{
+			"encoding": coding,
+			"error":    err.Error(),
+		})
+		return response
+	}
+
+	response.Headers["Content-Encoding"] = coding
+	addVary(response.Headers, "Accept-Encoding")
+	response.Body = base64.StdEncoding.EncodeToString(compressed)
+	response.IsBase64Encoded = true
+	return response
+}
+
+// responseBytes returns the body bytes of a response, decoding base64 bodies
+func responseBytes(response events.APIGatewayProxyResponse) []byte {
+	if response.IsBase64Encoded {
+		if data, err := base64.StdEncoding.DecodeString(response.Body); err == nil {
+			return data
+		}
+	}
+	return []byte(response.Body)
+}
+
+// addVary appends name to the Vary header unless it is already listed
+func addVary(headers map[string]string, name string) {
+	vary := headers["Vary"]
+	for _, existing := range strings.Split(vary, ",") {
+		if strings.EqualFold(strings.TrimSpace(existing), name) {
+			return
+		}
+	}
+	if vary == "" {
+		headers["Vary"] = name
+		return
+	}
+	headers["Vary"] = vary + ", " + name
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"echo-api/internal/compress"
+	"echo-api/internal/models"
+	"echo-api/internal/webhook"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// decodeCompressed reverses the base64 and content coding of a response body
+func decodeCompressed(t *testing.T, response events.APIGatewayProxyResponse) []byte {
+	t.Helper()
+	if !response.IsBase64Encoded {
+		t.Fatal("Expected compressed response to be base64 encoded")
+	}
+	data, err := base64.StdEncoding.DecodeString(response.Body)
+	if err != nil {
+		t.Fatalf("Failed to decode base64 body: %v", err)
+	}
+	decoded, err := compress.Decode(response.Headers["Content-Encoding"], data)
+	if err != nil {
+		t.Fatalf("Failed to decompress body: %v", err)
+	}
+	return decoded
+}
+
+func TestHandleRequest_ForcedCompression(t *testing.T) {
+	handler := NewLambdaHandler()
+	ctx := context.Background()
+
+	for path, coding := range map[string]string{"/gzip": "gzip", "/deflate": "deflate", "/brotli": "br"} {
+		response, err := handler.HandleRequest(ctx, events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: path})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.Headers["Content-Encoding"] != coding {
+			t.Errorf("For %s, expected Content-Encoding %s, got %s", path, coding, response.Headers["Content-Encoding"])
+		}
+
+		var echoResponse models.EchoResponse
+		if err := json.Unmarshal(decodeCompressed(t, response), &echoResponse); err != nil {
+			t.Fatalf("Failed to parse decompressed body: %v", err)
+		}
+		if echoResponse.Request.Path != path {
+			t.Errorf("Expected echoed path %s, got %s", path, echoResponse.Request.Path)
+		}
+	}
+}
+
+func TestHandleRequest_NegotiatedCompression(t *testing.T) {
+	handler := NewLambdaHandler()
+	handler.minCompressSize = 100
+	ctx := context.Background()
+
+	request := events.APIGatewayProxyRequest{
+		HTTPMethod: "POST",
+		Path:       "/test",
+		Headers:    map[string]string{"Accept-Encoding": "gzip, zstd"},
+		Body:       strings.Repeat("x", 200),
+	}
+	response, err := handler.HandleRequest(ctx, request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Headers["Content-Encoding"] != "zstd" {
+		t.Errorf("Expected zstd encoding, got %q", response.Headers["Content-Encoding"])
+	}
+	if response.Headers["Vary"] != "Accept, Accept-Encoding" {
+		t.Errorf("Expected Vary to list Accept-Encoding, got %q", response.Headers["Vary"])
+	}
+	decodeCompressed(t, response)
+
+	// Small bodies stay uncompressed
+	handler.minCompressSize = 1 << 20
+	response, _ = handler.HandleRequest(ctx, request)
+	if response.Headers["Content-Encoding"] != "" || response.IsBase64Encoded {
+		t.Errorf("Expected uncompressed response below the threshold, got %q", response.Headers["Content-Encoding"])
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", response.StatusCode)
+	}
+}
+
+func TestParseRequest_ContentEncoding(t *testing.T) {
+	handler := NewLambdaHandler()
+
+	compressed, _ := compress.Encode(compress.Gzip, []byte(`{"hello":"world"}`))
+	request := &events.APIGatewayProxyRequest{
+		HTTPMethod: "POST",
+		Path:       "/test",
+		Headers: map[string]string{
+			"Content-Type":     "application/json",
+			"Content-Encoding": "gzip",
+		},
+		Body:            base64.StdEncoding.EncodeToString(compressed),
+		IsBase64Encoded: true,
+	}
+
+	echoRequest := handler.parseRequest(request)
+	if echoRequest.Body != `{"hello":"world"}` || echoRequest.IsBase64Encoded {
+		t.Errorf("Expected decompressed text body, got %q (base64=%v)", echoRequest.Body, echoRequest.IsBase64Encoded)
+	}
+	if echoRequest.DecodedContentEncoding != "gzip" {
+		t.Errorf("Expected decoded content encoding gzip, got %q", echoRequest.DecodedContentEncoding)
+	}
+	if parsed, ok := echoRequest.ParsedBody.(map[string]interface{}); !ok || parsed["hello"] != "world" {
+		t.Errorf("Expected parsed body, got %#v", echoRequest.ParsedBody)
+	}
+
+	request.Headers["Content-Encoding"] = "compress"
+	if echoRequest := handler.parseRequest(request); echoRequest.ParseError == "" {
+		t.Error("Expected parse error for unsupported content encoding")
+	}
+}
+
+func TestHandleRequest_SignedCompressedWebhook(t *testing.T) {
+	handler := NewLambdaHandler()
+	handler.inspectors.webhooks = webhook.NewVerifier(webhook.Config{GitHubSecret: "secret"})
+	ctx := context.Background()
+
+	// The signature covers the gzip bytes on the wire, not the decoded JSON
+	compressed, _ := compress.Encode(compress.Gzip, []byte(`{"action":"opened"}`))
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(compressed)
+	request := events.APIGatewayProxyRequest{
+		HTTPMethod: "POST",
+		Path:       "/webhook",
+		Headers: map[string]string{
+			"Content-Type":        "application/json",
+			"Content-Encoding":    "gzip",
+			"X-Hub-Signature-256": "sha256=" + hex.EncodeToString(mac.Sum(nil)),
+		},
+		Body:            base64.StdEncoding.EncodeToString(compressed),
+		IsBase64Encoded: true,
+	}
+
+	response, err := handler.HandleRequest(ctx, request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var echoResponse models.EchoResponse
+	if err := json.Unmarshal([]byte(response.Body), &echoResponse); err != nil {
+		t.Fatalf("Failed to parse response body: %v", err)
+	}
+	if echoResponse.Request.Body != `{"action":"opened"}` {
+		t.Errorf("Expected decoded body, got %q", echoResponse.Request.Body)
+	}
+	if echoResponse.Webhook == nil || !echoResponse.Webhook.Verified {
+		t.Errorf("Expected verified webhook signature over the encoded body, got %+v", echoResponse.Webhook)
+	}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// scheduledDetailType is the detail-type of events sent by scheduled rules
+const scheduledDetailType = "Scheduled Event"
+
+// EventBridgeHandler echoes EventBridge (CloudWatch Events) events, including scheduled rules
+type EventBridgeHandler struct {
+	logger *logger.Logger
+}
+
+// NewEventBridgeHandler creates a new EventBridge handler instance
+func NewEventBridgeHandler() *EventBridgeHandler {
+	return &EventBridgeHandler{
+		logger: logger.New(),
+	}
+}
+
+// HandleRequest logs the event; a detail with "echo-fail": true fails the invocation so retry
+// policies and dead-letter queues can be exercised
+func (h *EventBridgeHandler) HandleRequest(ctx context.Context, event events.CloudWatchEvent) error {
+	echo := eventBridgeEcho(event)
+	fail := failRequested(echo.Detail)
+
+	h.logger.Info("Received EventBridge event", map[string]interface{}{
+		"record": echo,
+		"failed": fail,
+	})
+
+	if fail {
+		return fmt.Errorf("%s requested for event %s", failDirective, event.ID)
+	}
+	return nil
+}
+
+// eventBridgeEcho normalizes an EventBridge event into an echo record
+func eventBridgeEcho(event events.CloudWatchEvent) *models.EventEcho {
+	echo := models.NewEventEcho(event.Source)
+	echo.DetailType = event.DetailType
+	echo.ID = event.ID
+	echo.Time = event.Time.UTC().Format(time.RFC3339)
+	echo.Resources = event.Resources
+	echo.Attributes["account"] = event.AccountID
+	echo.Attributes["region"] = event.Region
+	echo.Attributes["version"] = event.Version
+	echo.Attributes["scheduled"] = event.DetailType == scheduledDetailType
+
+	if len(event.Detail) > 0 {
+		parseEventBody(echo, event.Detail)
+		// The detail is already JSON; the raw copy adds nothing
+		if echo.Detail != nil {
+			echo.Body = ""
+		}
+	}
+	return echo
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestEventBridgeHandler(t *testing.T) {
+	handler := NewEventBridgeHandler()
+	scheduled := events.CloudWatchEvent{
+		ID:         "e1",
+		DetailType: "Scheduled Event",
+		Source:     "aws.events",
+		Time:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Resources:  []string{"arn:aws:events:us-east-1:123456789012:rule/every-minute"},
+		Detail:     json.RawMessage(`{}`),
+	}
+
+	if err := handler.HandleRequest(context.Background(), scheduled); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	echo := eventBridgeEcho(scheduled)
+	if echo.Source != "aws.events" || echo.Time != "2024-01-02T03:04:05Z" || echo.Attributes["scheduled"] != true {
+		t.Errorf("Unexpected echo %+v", echo)
+	}
+	if echo.Body != "" || echo.Detail == nil {
+		t.Errorf("Expected only the parsed detail, got body %q detail %v", echo.Body, echo.Detail)
+	}
+
+	failing := scheduled
+	failing.DetailType = "Order Placed"
+	failing.Detail = json.RawMessage(`{"orderId":"o1","echo-fail":true}`)
+	if err := handler.HandleRequest(context.Background(), failing); err == nil {
+		t.Error("Expected echo-fail to fail the invocation")
+	}
+}
+
+func TestSNSHandler(t *testing.T) {
+	handler := NewSNSHandler()
+	record := events.SNSEventRecord{
+		EventSource:          "aws:sns",
+		EventSubscriptionArn: "arn:aws:sns:us-east-1:123456789012:echo:sub",
+		SNS: events.SNSEntity{
+			MessageID: "n1",
+			Type:      "Notification",
+			TopicArn:  "arn:aws:sns:us-east-1:123456789012:echo",
+			Subject:   "hello",
+			Message:   `{"a":1}`,
+		},
+	}
+
+	if err := handler.HandleRequest(context.Background(), events.SNSEvent{Records: []events.SNSEventRecord{record}}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	echo := snsEcho(record)
+	if echo.ID != "n1" || echo.DetailType != "Notification" || echo.Attributes["subject"] != "hello" || echo.Detail == nil {
+		t.Errorf("Unexpected echo %+v", echo)
+	}
+
+	record.SNS.MessageAttributes = map[string]interface{}{
+		"echo-fail": map[string]interface{}{"Type": "String", "Value": "true"},
+	}
+	if err := handler.HandleRequest(context.Background(), events.SNSEvent{Records: []events.SNSEventRecord{record}}); err == nil {
+		t.Error("Expected echo-fail to fail the invocation")
+	}
+}
+
+func TestPipesHandler(t *testing.T) {
+	handler := NewPipesHandler()
+	var batch []map[string]interface{}
+	json.Unmarshal([]byte(`[
+		{"messageId": "m1", "eventSource": "aws:sqs", "eventSourceARN": "arn:aws:sqs:us-east-1:123456789012:q", "body": "{\"a\":1}"},
+		{"eventID": "k1", "eventSource": "aws:kinesis", "data": "eyJiIjoyfQ=="},
+		{"id": "e1", "source": "app.orders", "detail-type": "Order Placed", "time": "2024-01-02T03:04:05Z", "detail": {"orderId": "o1"}}
+	]`), &batch)
+
+	output, err := handler.HandleRequest(context.Background(), batch)
+	if err != nil || len(output) != 3 {
+		t.Fatalf("Expected the batch to pass through, got %d events and %v", len(output), err)
+	}
+
+	sqs := pipesEcho(batch[0])
+	if sqs.Source != "aws:sqs" || sqs.ID != "m1" || sqs.Resources[0] != "arn:aws:sqs:us-east-1:123456789012:q" || sqs.Detail.(map[string]interface{})["a"] == nil {
+		t.Errorf("Unexpected SQS echo %+v", sqs)
+	}
+	kinesis := pipesEcho(batch[1])
+	if kinesis.Body != `{"b":2}` || kinesis.Detail.(map[string]interface{})["b"] == nil {
+		t.Errorf("Expected decoded Kinesis data, got %+v", kinesis)
+	}
+	bus := pipesEcho(batch[2])
+	if bus.DetailType != "Order Placed" || bus.Detail.(map[string]interface{})["orderId"] != "o1" {
+		t.Errorf("Unexpected EventBridge echo %+v", bus)
+	}
+
+	batch[2]["detail"] = map[string]interface{}{"echo-fail": true}
+	if _, err := handler.HandleRequest(context.Background(), batch); err == nil {
+		t.Error("Expected echo-fail to fail the batch")
+	}
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"echo-api/internal/codec"
+	"echo-api/internal/models"
+)
+
+// failDirective names the attribute or JSON field that asks an event handler to report a record as failed
+const failDirective = "echo-fail"
+
+// parseEventBody keeps the raw body and, when it is JSON, its parsed form as the detail
+func parseEventBody(echo *models.EventEcho, body []byte) {
+	echo.Body = string(body)
+	if !json.Valid(body) {
+		return
+	}
+	detail, err := codec.DecodeBody("application/json", body)
+	if err != nil {
+		echo.ParseError = err.Error()
+		return
+	}
+	echo.Detail = detail
+}
+
+// failRequested reports whether a parsed JSON payload carries "echo-fail": true
+func failRequested(detail interface{}) bool {
+	object, ok := detail.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	switch value := object[failDirective].(type) {
+	case bool:
+		return value
+	case string:
+		return isTrue(value)
+	}
+	return false
+}
+
+// isTrue reports whether a directive value means true
+func isTrue(value string) bool {
+	enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+	return err == nil && enabled
+}
+
+// epochMillis formats a millisecond Unix timestamp string as RFC 3339, returning "" when it is not a number
+func epochMillis(value string) string {
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return ""
+	}
+	return time.UnixMilli(millis).UTC().Format(time.RFC3339Nano)
+}
+
+// shardSummary tracks the sequence range and outcome of the records read from one shard
+type shardSummary struct {
+	Records       int    `json:"records"`
+	Failures      int    `json:"failures"`
+	FirstSequence string `json:"firstSequence"`
+	LastSequence  string `json:"lastSequence"`
+}
+
+// shardSummaries groups record summaries by shard
+type shardSummaries map[string]*shardSummary
+
+// add records a record of shard with its sequence number
+func (s shardSummaries) add(shard, sequence string, failed bool) {
+	summary, ok := s[shard]
+	if !ok {
+		summary = &shardSummary{FirstSequence: sequence}
+		s[shard] = summary
+	}
+	summary.Records++
+	summary.LastSequence = sequence
+	if failed {
+		summary.Failures++
+	}
+}
+
+// setEventData stores binary record data, keeping text as the body and base64 encoding anything else
+func setEventData(echo *models.EventEcho, data []byte) {
+	if utf8.Valid(data) {
+		parseEventBody(echo, data)
+		return
+	}
+	echo.Body = base64.StdEncoding.EncodeToString(data)
+	echo.Attributes["isBase64Encoded"] = true
+}
+
+// setWireData stores a broker message payload according to its sniffed format, recording the format
+// and any schema registry id in the attributes
+func setWireData(echo *models.EventEcho, data []byte) {
+	wire := codec.Sniff(data)
+	echo.Attributes["format"] = wire.Format
+	if wire.SchemaID != nil {
+		echo.Attributes["schemaId"] = *wire.SchemaID
+	}
+
+	switch wire.Format {
+	case codec.FormatJSON:
+		parseEventBody(echo, wire.Payload)
+	case codec.FormatText:
+		echo.Body = string(wire.Payload)
+	default:
+		echo.Body = base64.StdEncoding.EncodeToString(wire.Payload)
+		echo.Attributes["isBase64Encoded"] = true
+	}
+}
+
+// printable returns data as a string when it is UTF-8 and base64 encoded otherwise
+func printable(data []byte) string {
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
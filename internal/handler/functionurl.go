@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"echo-api/internal/models"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// FunctionURLHandler serves the echo API behind a buffered Lambda Function URL
+type FunctionURLHandler struct {
+	proxy *LambdaHandler
+}
+
+// NewFunctionURLHandler creates a new Function URL handler instance
+func NewFunctionURLHandler() *FunctionURLHandler {
+	return &FunctionURLHandler{
+		proxy: NewLambdaHandler(),
+	}
+}
+
+// HandleRequest adapts the payload 2.0 request to the proxy handler and echoes its request context
+func (f *FunctionURLHandler) HandleRequest(ctx context.Context, request events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error) {
+	response, err := f.proxy.serve(ctx, proxyRequestFromURL(request), functionURLInfo(request))
+	if err != nil {
+		return events.LambdaFunctionURLResponse{}, err
+	}
+	return functionURLResponse(response), nil
+}
+
+// functionURLInfo extracts the request context echoed for Function URL requests
+func functionURLInfo(request events.LambdaFunctionURLRequest) *models.FunctionURLInfo {
+	requestContext := request.RequestContext
+	info := &models.FunctionURLInfo{
+		URLID:      requestContext.APIID,
+		DomainName: requestContext.DomainName,
+		RequestID:  requestContext.RequestID,
+		Time:       requestContext.Time,
+		HTTP: models.FunctionURLHTTP{
+			Method:    requestContext.HTTP.Method,
+			Path:      requestContext.HTTP.Path,
+			Protocol:  requestContext.HTTP.Protocol,
+			SourceIP:  requestContext.HTTP.SourceIP,
+			UserAgent: requestContext.HTTP.UserAgent,
+		},
+		RawQuery: request.RawQueryString,
+		Cookies:  request.Cookies,
+	}
+
+	// Only Function URLs with AuthType AWS_IAM carry an authorizer block
+	if requestContext.Authorizer != nil && requestContext.Authorizer.IAM != nil {
+		iam := requestContext.Authorizer.IAM
+		info.IAM = &models.FunctionURLIAMAuth{
+			AccessKey: iam.AccessKey,
+			AccountID: iam.AccountID,
+			CallerID:  iam.CallerID,
+			UserARN:   iam.UserARN,
+			UserID:    iam.UserID,
+		}
+	}
+	return info
+}
+
+// functionURLResponse converts a proxy response; Function URLs only set cookies from the cookies field
+func functionURLResponse(response events.APIGatewayProxyResponse) events.LambdaFunctionURLResponse {
+	headers := make(map[string]string, len(response.Headers))
+	var cookies []string
+	for name, value := range response.Headers {
+		if http.CanonicalHeaderKey(name) == "Set-Cookie" {
+			cookies = append(cookies, value)
+			continue
+		}
+		headers[name] = value
+	}
+	for name, values := range response.MultiValueHeaders {
+		if http.CanonicalHeaderKey(name) == "Set-Cookie" {
+			cookies = append(cookies, values...)
+		}
+	}
+
+	return events.LambdaFunctionURLResponse{
+		StatusCode:      response.StatusCode,
+		Headers:         headers,
+		Body:            response.Body,
+		IsBase64Encoded: response.IsBase64Encoded,
+		Cookies:         cookies,
+	}
+}
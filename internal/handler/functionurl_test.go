@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"echo-api/internal/models"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestFunctionURLHandler_Echo(t *testing.T) {
+	handler := NewFunctionURLHandler()
+
+	request := urlRequest("POST", "/debug", nil)
+	request.Body = base64.StdEncoding.EncodeToString([]byte(`{"hello":"world"}`))
+	request.IsBase64Encoded = true
+	request.RequestContext.Authorizer = &events.LambdaFunctionURLRequestContextAuthorizerDescription{
+		IAM: &events.LambdaFunctionURLRequestContextAuthorizerIAMDescription{
+			AccessKey: "AKIAEXAMPLE",
+			AccountID: "123456789012",
+			CallerID:  "AIDAEXAMPLE",
+			UserARN:   "arn:aws:iam::123456789012:user/alice",
+		},
+	}
+
+	response, err := handler.HandleRequest(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.StatusCode != 200 {
+		t.Fatalf("Expected status 200, got %d", response.StatusCode)
+	}
+
+	var echo models.EchoResponse
+	if err := json.Unmarshal([]byte(response.Body), &echo); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if echo.Request.Headers["cookie"] != "a=1; b=2" {
+		t.Errorf("Expected cookies in the cookie header, got %q", echo.Request.Headers["cookie"])
+	}
+	if echo.Request.Body != `{"hello":"world"}` {
+		t.Errorf("Expected the decoded body, got %q", echo.Request.Body)
+	}
+	if echo.FunctionURL == nil || echo.FunctionURL.HTTP.Method != "POST" || len(echo.FunctionURL.Cookies) != 2 {
+		t.Fatalf("Expected the Function URL context, got %+v", echo.FunctionURL)
+	}
+	if echo.FunctionURL.IAM == nil || echo.FunctionURL.IAM.UserARN != "arn:aws:iam::123456789012:user/alice" {
+		t.Errorf("Expected the IAM caller, got %+v", echo.FunctionURL.IAM)
+	}
+}
+
+func TestFunctionURLHandler_Cookies(t *testing.T) {
+	handler := NewFunctionURLHandler()
+
+	response, err := handler.HandleRequest(context.Background(), urlRequest("GET", "/response-headers", map[string]string{"Set-Cookie": "session=abc"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Cookies) != 1 || response.Cookies[0] != "session=abc" {
+		t.Errorf("Expected the cookie in the cookies field, got %v", response.Cookies)
+	}
+	if _, ok := response.Headers["Set-Cookie"]; ok {
+		t.Error("Expected Set-Cookie to be removed from the headers")
+	}
+
+	// Binary formats stay base64 encoded
+	response, _ = handler.HandleRequest(context.Background(), urlRequest("GET", "/", map[string]string{"format": "msgpack"}))
+	if !response.IsBase64Encoded {
+		t.Error("Expected a base64 encoded body")
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/internal/recorder"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// historyPrefix is the path prefix of the history endpoints, which are never recorded themselves
+const historyPrefix = "/_history"
+
+// Limits of a history listing
+const (
+	defaultHistoryLimit = 100
+	maxHistoryLimit     = 1000
+)
+
+// recordRequest stores the request when recording is enabled, leaving out every bin path so tokens never leak;
+// failures are logged and never affect the echo
+func (h *LambdaHandler) recordRequest(ctx context.Context, request *models.EchoRequest) {
+	if h.recorder == nil || request.Path == historyPrefix || strings.HasPrefix(request.Path, historyPrefix+"/") || isBinPath(request.Path) {
+		return
+	}
+
+	record := recorder.NewRecord(request, h.now())
+	if err := h.recorder.Put(ctx, record); err != nil {
+		h.logger.Warn("Failed to record request", map[string]interface{}{
+			"error": err.Error(),
+			"path":  request.Path,
+		})
+	}
+}
+
+// handleHistory lists recorded requests, filtered by ?path=, ?method=, ?header=, ?since=, ?until= and ?limit=
+func (h *LambdaHandler) handleHistory(ctx context.Context, r *routeRequest) (events.APIGatewayProxyResponse, error) {
+	if h.recorder == nil {
+		return h.recordingDisabled()
+	}
+
+	filter, message := historyFilter(r.proxy.QueryStringParameters)
+	if message != "" {
+		return h.createErrorResponse(http.StatusBadRequest, "Bad Request", message)
+	}
+
+	records, err := h.recorder.List(ctx, filter)
+	if err != nil {
+		return h.historyStoreError(err)
+	}
+	return h.historyResponse(http.StatusOK, &models.HistoryResponse{Records: records, Count: len(records)})
+}
+
+// handleHistoryRecord returns a single recorded request
+func (h *LambdaHandler) handleHistoryRecord(ctx context.Context, r *routeRequest) (events.APIGatewayProxyResponse, error) {
+	if h.recorder == nil {
+		return h.recordingDisabled()
+	}
+
+	record, err := h.recorder.Get(ctx, r.params["id"])
+	if errors.Is(err, recorder.ErrNotFound) {
+		return h.createErrorResponse(http.StatusNotFound, "Not Found", "No recorded request with id "+r.params["id"])
+	}
+	if err != nil {
+		return h.historyStoreError(err)
+	}
+	return h.historyResponse(http.StatusOK, record)
+}
+
+// handleHistoryClear removes every recorded request; it only accepts POST
+func (h *LambdaHandler) handleHistoryClear(ctx context.Context, r *routeRequest) (events.APIGatewayProxyResponse, error) {
+	if h.recorder == nil {
+		return h.recordingDisabled()
+	}
+	if r.proxy.HTTPMethod != http.MethodPost {
+		return h.createErrorResponse(http.StatusMethodNotAllowed, "Method Not Allowed", "Use POST to clear the history")
+	}
+
+	if err := h.recorder.Clear(ctx); err != nil {
+		return h.historyStoreError(err)
+	}
+	h.logger.Info("Request history cleared", map[string]interface{}{})
+	return events.APIGatewayProxyResponse{
+		StatusCode: http.StatusNoContent,
+		Headers:    responseHeaders("application/json"),
+	}, nil
+}
+
+// historyFilter parses the listing query; message describes the first invalid parameter
+func historyFilter(query map[string]string) (recorder.Filter, string) {
+	filter := recorder.Filter{
+		Path:   query["path"],
+		Method: query["method"],
+		Header: query["header"],
+		Limit:  defaultHistoryLimit,
+	}
+
+	if value := query["limit"]; value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxHistoryLimit {
+			return filter, "limit must be an integer between 1 and " + strconv.Itoa(maxHistoryLimit)
+		}
+		filter.Limit = limit
+	}
+
+	for name, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := query[name]
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, name + " must be an RFC 3339 timestamp"
+		}
+		*target = parsed
+	}
+	return filter, ""
+}
+
+// historyResponse renders v as JSON
+func (h *LambdaHandler) historyResponse(statusCode int, v interface{}) (events.APIGatewayProxyResponse, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return h.createErrorResponse(http.StatusInternalServerError, "Internal Server Error", "Failed to process response")
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: statusCode,
+		Headers:    responseHeaders("application/json", "Cache-Control", "no-store"),
+		Body:       string(body),
+	}, nil
+}
+
+// recordingDisabled answers the history endpoints when no store is configured
+func (h *LambdaHandler) recordingDisabled() (events.APIGatewayProxyResponse, error) {
+	return h.createErrorResponse(http.StatusNotFound, "Not Found", "Request recording is disabled; set RECORDER_STORE to enable it")
+}
+
+// historyStoreError reports a store failure
+func (h *LambdaHandler) historyStoreError(err error) (events.APIGatewayProxyResponse, error) {
+	h.logger.Error("Request history store failed", map[string]interface{}{
+		"error": err.Error(),
+	})
+	return h.createErrorResponse(http.StatusBadGateway, "Bad Gateway", "The request history store is unavailable")
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"echo-api/internal/models"
+	"echo-api/internal/recorder"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestHandleRequest_History(t *testing.T) {
+	handler := NewLambdaHandler()
+	handler.recorder = recorder.NewMemoryStore(10)
+	ctx := context.Background()
+
+	send := func(method, path string, query map[string]string) events.APIGatewayProxyResponse {
+		response, err := handler.HandleRequest(ctx, events.APIGatewayProxyRequest{
+			HTTPMethod:            method,
+			Path:                  path,
+			Headers:               map[string]string{"X-GitHub-Event": "push"},
+			QueryStringParameters: query,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return response
+	}
+
+	send("POST", "/hooks/github", nil)
+	send("GET", "/status", nil)
+
+	// History requests are not recorded themselves
+	var history models.HistoryResponse
+	json.Unmarshal([]byte(send("GET", "/_history", nil).Body), &history)
+	if history.Count != 2 || history.Records[0].Request.Path != "/status" {
+		t.Fatalf("Expected two records newest first, got %+v", history)
+	}
+
+	json.Unmarshal([]byte(send("GET", "/_history", map[string]string{"path": "/hooks/*", "method": "POST"}).Body), &history)
+	if history.Count != 1 || history.Records[0].Request.Headers["X-GitHub-Event"] != "push" {
+		t.Errorf("Expected the webhook, got %+v", history)
+	}
+
+	var record models.RecordedRequest
+	response := send("GET", "/_history/"+history.Records[0].ID, nil)
+	json.Unmarshal([]byte(response.Body), &record)
+	if response.StatusCode != http.StatusOK || record.Request.Path != "/hooks/github" {
+		t.Errorf("Expected the record, got %d %s", response.StatusCode, response.Body)
+	}
+
+	if response := send("GET", "/_history/missing", nil); response.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", response.StatusCode)
+	}
+	if response := send("GET", "/_history", map[string]string{"since": "yesterday"}); response.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", response.StatusCode)
+	}
+	if response := send("GET", "/_history/clear", nil); response.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", response.StatusCode)
+	}
+
+	if response := send("POST", "/_history/clear", nil); response.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", response.StatusCode)
+	}
+	json.Unmarshal([]byte(send("GET", "/_history", nil).Body), &history)
+	if history.Count != 0 {
+		t.Errorf("Expected an empty history, got %d records", history.Count)
+	}
+}
+
+func TestHandleRequest_HistoryDisabled(t *testing.T) {
+	handler := NewLambdaHandler()
+
+	response, err := handler.HandleRequest(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: "/_history"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 without a store, got %d", response.StatusCode)
+	}
+}
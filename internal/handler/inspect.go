@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"echo-api/internal/jwt"
+	"echo-api/internal/models"
+	"echo-api/internal/sigv4"
+	"echo-api/internal/webhook"
+	"echo-api/pkg/logger"
+)
+
+// inspectors annotates echo responses with authentication details found in the request
+type inspectors struct {
+	tokens   *jwt.Inspector
+	webhooks *webhook.Verifier
+	sigv4    *sigv4.Inspector
+}
+
+// newInspectors creates the inspectors from environment configuration
+func newInspectors(l *logger.Logger) inspectors {
+	// Configuration problems fall back to defaults so they never break the echo itself
+	keys, err := jwt.KeySetFromEnv()
+	if err != nil {
+		l.Warn("Failed to load JWT key set", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	webhookConfig, err := webhook.ConfigFromEnv()
+	if err != nil {
+		l.Warn("Failed to load webhook configuration", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	return inspectors{
+		tokens:   jwt.NewInspector(keys),
+		webhooks: webhook.NewVerifier(webhookConfig),
+		sigv4:    sigv4.NewInspector(sigv4.CredentialsFromEnv()),
+	}
+}
+
+// annotate adds the token and signature sections to the response; signedPath is the path as the client sent it
+func (i inspectors) annotate(response *models.EchoResponse, signedPath string) {
+	request := &response.Request
+
+	if i.tokens != nil {
+		if token, ok := jwt.BearerToken(request.Header("Authorization")); ok {
+			response.Token = i.tokens.Inspect(token)
+		}
+	}
+	if i.webhooks != nil {
+		response.Webhook = i.webhooks.Verify(request)
+	}
+	if i.sigv4 != nil {
+		response.SigV4 = i.sigv4.Inspect(request, signedPath)
+	}
+}
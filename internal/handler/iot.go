@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"echo-api/internal/models"
+	"echo-api/pkg/logger"
+)
+
+// IoTHandler echoes payloads delivered by IoT Core rule actions
+type IoTHandler struct {
+	logger *logger.Logger
+}
+
+// NewIoTHandler creates a new IoT rule handler instance
+func NewIoTHandler() *IoTHandler {
+	return &IoTHandler{
+		logger: logger.New(),
+	}
+}
+
+// HandleRequest logs the payload produced by the rule's SQL statement. Selecting
+// topic() AS topic, clientid() AS clientId and timestamp() AS timestamp adds them to the echo
+func (h *IoTHandler) HandleRequest(ctx context.Context, payload json.RawMessage) error {
+	h.logger.Info("Received IoT message", map[string]interface{}{
+		"record": iotEcho(payload),
+	})
+	return nil
+}
+
+// iotEcho normalizes a rule payload; payloads that are not JSON objects are kept as the body
+func iotEcho(payload json.RawMessage) *models.EventEcho {
+	echo := models.NewEventEcho("aws:iot")
+	parseEventBody(echo, payload)
+
+	fields, ok := echo.Detail.(map[string]interface{})
+	if !ok {
+		return echo
+	}
+
+	echo.DetailType = firstString(fields, "topic")
+	if clientID := firstString(fields, "clientId", "clientid"); clientID != "" {
+		echo.Attributes["clientId"] = clientID
+	}
+	if principal := firstString(fields, "principal"); principal != "" {
+		echo.Attributes["principal"] = principal
+	}
+	// timestamp() yields epoch milliseconds
+	if timestamp, ok := fields["timestamp"].(int64); ok {
+		echo.Time = epochMillis(strconv.FormatInt(timestamp, 10))
+	}
+	return echo
+}
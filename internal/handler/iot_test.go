@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIoTEcho(t *testing.T) {
+	echo := iotEcho(json.RawMessage(`{"temperature": 21.5, "topic": "sensors/room1", "clientId": "device-1", "timestamp": 1700000000000}`))
+	if echo.Source != "aws:iot" || echo.DetailType != "sensors/room1" || echo.Attributes["clientId"] != "device-1" {
+		t.Errorf("Unexpected echo %+v", echo)
+	}
+	if echo.Time != "2023-11-14T22:13:20Z" {
+		t.Errorf("Expected the rule timestamp, got %s", echo.Time)
+	}
+	if detail := echo.Detail.(map[string]interface{}); detail["temperature"] == nil {
+		t.Errorf("Expected the payload as detail, got %v", detail)
+	}
+
+	// A base64 payload selected with encode(*, 'base64') is a JSON string
+	echo = iotEcho(json.RawMessage(`"AAEC"`))
+	if echo.Detail != "AAEC" || echo.DetailType != "" {
+		t.Errorf("Unexpected echo %+v", echo)
+	}
+}
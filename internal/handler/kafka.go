@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// KafkaHandler echoes records from Amazon MSK and self-managed Kafka event sources
+type KafkaHandler struct {
+	logger *logger.Logger
+}
+
+// NewKafkaHandler creates a new Kafka handler instance
+func NewKafkaHandler() *KafkaHandler {
+	return &KafkaHandler{
+		logger: logger.New(),
+	}
+}
+
+// HandleRequest logs every record with its decoded key, value and headers, followed by the offset
+// range of each topic-partition
+func (h *KafkaHandler) HandleRequest(ctx context.Context, event events.KafkaEvent) error {
+	partitions := make(shardSummaries)
+
+	// The batch is keyed by topic-partition; sort it so the log follows a stable order
+	keys := make([]string, 0, len(event.Records))
+	for key := range event.Records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	count := 0
+	for _, key := range keys {
+		for _, record := range event.Records[key] {
+			h.logger.Info("Received Kafka record", map[string]interface{}{
+				"record": kafkaEcho(event, record),
+			})
+			partitions.add(key, strconv.FormatInt(record.Offset, 10), false)
+			count++
+		}
+	}
+
+	h.logger.Info("Processed Kafka batch", map[string]interface{}{
+		"event_source":      event.EventSource,
+		"bootstrap_servers": event.BootstrapServers,
+		"records":           count,
+		"partitions":        partitions,
+	})
+	return nil
+}
+
+// kafkaEcho normalizes a Kafka record, decoding its base64 key and value
+func kafkaEcho(event events.KafkaEvent, record events.KafkaRecord) *models.EventEcho {
+	echo := models.NewEventEcho(event.EventSource)
+	echo.DetailType = record.Topic
+	echo.ID = fmt.Sprintf("%s-%d@%d", record.Topic, record.Partition, record.Offset)
+	echo.Time = record.Timestamp.UTC().Format(time.RFC3339Nano)
+	if event.EventSourceARN != "" {
+		echo.Resources = []string{event.EventSourceARN}
+	}
+	echo.Attributes["topic"] = record.Topic
+	echo.Attributes["partition"] = record.Partition
+	echo.Attributes["offset"] = record.Offset
+	echo.Attributes["timestampType"] = record.TimestampType
+	echo.Attributes["headers"] = kafkaHeaders(record.Headers)
+
+	if record.Key != "" {
+		if key, err := base64.StdEncoding.DecodeString(record.Key); err == nil {
+			echo.Attributes["key"] = printable(key)
+		} else {
+			echo.Attributes["key"] = record.Key
+		}
+	}
+
+	value, err := base64.StdEncoding.DecodeString(record.Value)
+	if err != nil {
+		echo.Body = record.Value
+		echo.ParseError = err.Error()
+		return echo
+	}
+	setWireData(echo, value)
+	return echo
+}
+
+// kafkaHeaders converts the byte-array headers into strings, keeping their order and repeated names
+func kafkaHeaders(headers []map[string]events.JSONNumberBytes) []map[string]string {
+	converted := make([]map[string]string, 0, len(headers))
+	for _, header := range headers {
+		entry := make(map[string]string, len(header))
+		for name, value := range header {
+			entry[name] = printable(value)
+		}
+		converted = append(converted, entry)
+	}
+	return converted
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"echo-api/internal/kpl"
+	"echo-api/internal/models"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// KinesisHandler echoes Kinesis records, unpacking KPL aggregated records
+type KinesisHandler struct {
+	logger *logger.Logger
+}
+
+// NewKinesisHandler creates a new Kinesis handler instance
+func NewKinesisHandler() *KinesisHandler {
+	return &KinesisHandler{
+		logger: logger.New(),
+	}
+}
+
+// HandleRequest logs every user record and reports records whose JSON data carries "echo-fail": true
+// as batch item failures, followed by a per-shard sequence summary
+func (h *KinesisHandler) HandleRequest(ctx context.Context, event events.KinesisEvent) (events.KinesisEventResponse, error) {
+	response := events.KinesisEventResponse{BatchItemFailures: []events.KinesisBatchItemFailure{}}
+	shards := make(shardSummaries)
+
+	for _, record := range event.Records {
+		failed := false
+		for _, echo := range kinesisEchoes(record) {
+			fail := failRequested(echo.Detail)
+			h.logger.Info("Received Kinesis record", map[string]interface{}{
+				"record": echo,
+				"failed": fail,
+			})
+			failed = failed || fail
+		}
+
+		shards.add(kinesisShardID(record.EventID), record.Kinesis.SequenceNumber, failed)
+		if failed {
+			response.BatchItemFailures = append(response.BatchItemFailures, events.KinesisBatchItemFailure{ItemIdentifier: record.Kinesis.SequenceNumber})
+		}
+	}
+
+	h.logger.Info("Processed Kinesis batch", map[string]interface{}{
+		"records":  len(event.Records),
+		"failures": len(response.BatchItemFailures),
+		"shards":   shards,
+	})
+	return response, nil
+}
+
+// kinesisEchoes normalizes a Kinesis record into one echo per user record
+func kinesisEchoes(record events.KinesisEventRecord) []*models.EventEcho {
+	newEcho := func(id string) *models.EventEcho {
+		echo := models.NewEventEcho(record.EventSource)
+		echo.DetailType = record.EventName
+		echo.ID = id
+		echo.Time = record.Kinesis.ApproximateArrivalTimestamp.UTC().Format(time.RFC3339Nano)
+		echo.Resources = []string{record.EventSourceArn}
+		echo.Attributes["shardId"] = kinesisShardID(record.EventID)
+		echo.Attributes["partitionKey"] = record.Kinesis.PartitionKey
+		echo.Attributes["sequenceNumber"] = record.Kinesis.SequenceNumber
+		if record.Kinesis.EncryptionType != "" {
+			echo.Attributes["encryptionType"] = record.Kinesis.EncryptionType
+		}
+		return echo
+	}
+
+	data := record.Kinesis.Data
+	if kpl.IsAggregated(data) {
+		userRecords, err := kpl.Deaggregate(data)
+		if err == nil {
+			echoes := make([]*models.EventEcho, 0, len(userRecords))
+			for index, userRecord := range userRecords {
+				echo := newEcho(record.EventID + ":" + strconv.Itoa(index))
+				echo.Attributes["aggregated"] = true
+				echo.Attributes["subSequenceNumber"] = index
+				echo.Attributes["partitionKey"] = userRecord.PartitionKey
+				if userRecord.ExplicitHashKey != "" {
+					echo.Attributes["explicitHashKey"] = userRecord.ExplicitHashKey
+				}
+				setEventData(echo, userRecord.Data)
+				echoes = append(echoes, echo)
+			}
+			return echoes
+		}
+
+		// Keep the raw data when the aggregate cannot be unpacked
+		echo := newEcho(record.EventID)
+		setEventData(echo, data)
+		echo.ParseError = err.Error()
+		return []*models.EventEcho{echo}
+	}
+
+	echo := newEcho(record.EventID)
+	setEventData(echo, data)
+	return []*models.EventEcho{echo}
+}
+
+// kinesisShardID extracts the shard from an event id such as "shardId-000000000000:4954..."
+func kinesisShardID(eventID string) string {
+	shard, _, _ := strings.Cut(eventID, ":")
+	return shard
+}
@@ -2,11 +2,18 @@ package handler
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"log"
 	"net/http"
+	"time"
+	"unicode/utf8"
 
+	"echo-api/internal/codec"
+	"echo-api/internal/compress"
 	"echo-api/internal/models"
+	"echo-api/internal/recorder"
+	"echo-api/internal/render"
 	"echo-api/pkg/logger"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -14,18 +21,52 @@ import (
 
 // LambdaHandler handles AWS Lambda proxy requests
 type LambdaHandler struct {
-	logger *logger.Logger
+	logger          *logger.Logger
+	inspectors      inspectors
+	minCompressSize int
+	recorder        recorder.Store
+	bins            recorder.BinStore
+	binLimits       recorder.BinLimits
+	now             func() time.Time
 }
 
 // NewLambdaHandler creates a new Lambda handler instance
 func NewLambdaHandler() *LambdaHandler {
+	l := logger.New()
+
+	// Recording is optional; a misconfigured store disables it rather than the echo
+	store, err := recorder.StoreFromEnv()
+	if err != nil {
+		l.Warn("Failed to configure request recording", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	bins, binLimits, err := recorder.BinsFromEnv()
+	if err != nil {
+		l.Warn("Failed to configure bins", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
 	return &LambdaHandler{
-		logger: logger.New(),
+		logger:          l,
+		inspectors:      newInspectors(l),
+		minCompressSize: compressionMinSize(l),
+		recorder:        store,
+		bins:            bins,
+		binLimits:       binLimits,
+		now:             time.Now,
 	}
 }
 
 // HandleRequest processes the incoming API Gateway proxy request
 func (h *LambdaHandler) HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return h.serve(ctx, request, nil)
+}
+
+// serve routes a proxy request; functionURL carries the Function URL context when the request was adapted from one
+func (h *LambdaHandler) serve(ctx context.Context, request events.APIGatewayProxyRequest, functionURL *models.FunctionURLInfo) (events.APIGatewayProxyResponse, error) {
 	h.logger.Info("Processing request", map[string]interface{}{
 		"method":       request.HTTPMethod,
 		"path":         request.Path,
@@ -44,12 +85,51 @@ func (h *LambdaHandler) HandleRequest(ctx context.Context, request events.APIGat
 
 	// Parse the request
 	echoRequest := h.parseRequest(&request)
+	h.recordRequest(ctx, echoRequest)
+
+	// Serve the matching route; every other path is echoed
+	handle, params := matchRoute(request.Path)
+	response, err := handle(h, ctx, &routeRequest{proxy: &request, echo: echoRequest, params: params, functionURL: functionURL})
+	if err != nil {
+		return response, err
+	}
+
+	// Compress the response when the client accepts it
+	return h.negotiateEncoding(response, echoRequest.Header("Accept-Encoding")), nil
+}
+
+// handleEcho echoes the request in the negotiated format
+func (h *LambdaHandler) handleEcho(ctx context.Context, r *routeRequest) (events.APIGatewayProxyResponse, error) {
+	format, ok := h.negotiateFormat(r)
+	if !ok {
+		return h.createErrorResponse(http.StatusNotAcceptable, "Not Acceptable", "Supported formats: "+render.Supported())
+	}
+	return h.renderEcho(r, format, h.newEchoResponse(r))
+}
+
+// negotiateFormat picks the output format from ?format= or the Accept header
+func (h *LambdaHandler) negotiateFormat(r *routeRequest) (*render.Format, bool) {
+	format, ok := render.Negotiate(r.echo.Header("Accept"), r.proxy.QueryStringParameters["format"])
+	if !ok {
+		h.logger.Warn("No acceptable format", map[string]interface{}{
+			"accept": r.echo.Header("Accept"),
+			"format": r.proxy.QueryStringParameters["format"],
+		})
+	}
+	return format, ok
+}
 
-	// Create echo response
-	echoResponse := models.NewEchoResponse(echoRequest, "Request successfully echoed")
+// newEchoResponse creates the echo response with its inspection sections
+func (h *LambdaHandler) newEchoResponse(r *routeRequest) *models.EchoResponse {
+	echoResponse := models.NewEchoResponse(r.echo, "Request successfully echoed")
+	echoResponse.FunctionURL = r.functionURL
+	h.inspectors.annotate(echoResponse, signedPath(r.proxy))
+	return echoResponse
+}
 
-	// Convert response to JSON
-	responseBody, err := echoResponse.ToJSON()
+// renderEcho renders the echo response in format with the standard headers
+func (h *LambdaHandler) renderEcho(r *routeRequest, format *render.Format, echoResponse *models.EchoResponse) (events.APIGatewayProxyResponse, error) {
+	responseBody, err := format.Render(echoResponse)
 	if err != nil {
 		h.logger.Error("Failed to marshal response", map[string]interface{}{
 			"error": err.Error(),
@@ -57,24 +137,26 @@ func (h *LambdaHandler) HandleRequest(ctx context.Context, request events.APIGat
 		return h.createErrorResponse(http.StatusInternalServerError, "Internal Server Error", "Failed to process response")
 	}
 
+	// Binary formats travel base64 encoded through API Gateway
+	if format.Binary {
+		responseBody = base64.StdEncoding.EncodeToString([]byte(responseBody))
+	}
+
 	// Log the successful response with full response body
 	h.logger.Info("Request successfully echoed", map[string]interface{}{
 		"response_size": len(responseBody),
-		"method":        request.HTTPMethod,
-		"path":          request.Path,
+		"method":        r.proxy.HTTPMethod,
+		"path":          r.proxy.Path,
 		"response_body": responseBody,
+		"format":        format.Name,
 		"message":       "Request processed successfully",
 	})
 
 	return events.APIGatewayProxyResponse{
-		StatusCode: http.StatusOK,
-		Headers: map[string]string{
-			"Content-Type":                 "application/json",
-			"Access-Control-Allow-Origin":  "*",
-			"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
-			"Access-Control-Allow-Headers": "Content-Type, Authorization",
-		},
-		Body: responseBody,
+		StatusCode:      http.StatusOK,
+		Headers:         responseHeaders(format.ContentType, "Vary", "Accept"),
+		Body:            responseBody,
+		IsBase64Encoded: format.Binary,
 	}, nil
 }
 
@@ -103,13 +185,67 @@ func (h *LambdaHandler) parseRequest(request *events.APIGatewayProxyRequest) *mo
 	}
 
 	// Create the echo request
-	return models.NewEchoRequest(
+	echoRequest := models.NewEchoRequest(
 		request.HTTPMethod,
 		request.Path,
 		headers,
 		queryParams,
 		request.Body,
 	)
+	echoRequest.IsBase64Encoded = request.IsBase64Encoded
+	decodeBody(echoRequest)
+
+	return echoRequest
+}
+
+// decodeBody removes base64 and content codings from the body and fills the parsed-body view
+func decodeBody(request *models.EchoRequest) {
+	body, err := request.RawBody()
+	if err != nil {
+		request.ParseError = "body is not valid base64"
+		return
+	}
+
+	if contentEncoding := request.Header("Content-Encoding"); contentEncoding != "" {
+		decoded, err := compress.Decode(contentEncoding, body)
+		if err != nil {
+			request.ParseError = err.Error()
+			return
+		}
+		// Signatures are computed over the encoded bytes, so keep them for the inspectors
+		request.SetWireBody(body)
+		body = decoded
+		request.DecodedContentEncoding = contentEncoding
+		setBody(request, body)
+	} else if request.IsBase64Encoded {
+		setBody(request, body)
+	}
+
+	parsed, err := codec.DecodeBody(request.Header("Content-Type"), body)
+	if err != nil {
+		request.ParseError = err.Error()
+		return
+	}
+	request.ParsedBody = parsed
+}
+
+// setBody stores body as text when it is valid UTF-8, otherwise as base64
+func setBody(request *models.EchoRequest, body []byte) {
+	if utf8.Valid(body) {
+		request.Body = string(body)
+		request.IsBase64Encoded = false
+		return
+	}
+	request.Body = base64.StdEncoding.EncodeToString(body)
+	request.IsBase64Encoded = true
+}
+
+// signedPath returns the path the client sent, including the stage prefix API Gateway strips
+func signedPath(request *events.APIGatewayProxyRequest) string {
+	if request.RequestContext.Path != "" {
+		return request.RequestContext.Path
+	}
+	return request.Path
 }
 
 // createErrorResponse creates a standardized error response
@@ -132,12 +268,21 @@ func (h *LambdaHandler) createErrorResponse(statusCode int, error, message strin
 
 	return events.APIGatewayProxyResponse{
 		StatusCode: statusCode,
-		Headers: map[string]string{
-			"Content-Type":                 "application/json",
-			"Access-Control-Allow-Origin":  "*",
-			"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
-			"Access-Control-Allow-Headers": "Content-Type, Authorization",
-		},
-		Body: responseBody,
+		Headers:    responseHeaders("application/json"),
+		Body:       responseBody,
 	}, nil
+}
+
+// responseHeaders builds the standard response headers; extra holds additional name/value pairs
+func responseHeaders(contentType string, extra ...string) map[string]string {
+	headers := map[string]string{
+		"Content-Type":                 contentType,
+		"Access-Control-Allow-Origin":  "*",
+		"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
+		"Access-Control-Allow-Headers": "Content-Type, Authorization",
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		headers[extra[i]] = extra[i+1]
+	}
+	return headers
 }
\ No newline at end of file
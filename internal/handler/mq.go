@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"sort"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// MQHandler echoes Amazon MQ messages from ActiveMQ and RabbitMQ brokers
+type MQHandler struct {
+	logger *logger.Logger
+}
+
+// NewMQHandler creates a new Amazon MQ handler instance
+func NewMQHandler() *MQHandler {
+	return &MQHandler{
+		logger: logger.New(),
+	}
+}
+
+// HandleActiveMQ logs every ActiveMQ message with its decoded data and properties
+func (h *MQHandler) HandleActiveMQ(ctx context.Context, event events.ActiveMQEvent) error {
+	destinations := make(map[string]int)
+	for _, message := range event.Messages {
+		h.logger.Info("Received ActiveMQ message", map[string]interface{}{
+			"record": activeMQEcho(event, message),
+		})
+		destinations[message.Destination.PhysicalName]++
+	}
+
+	h.logger.Info("Processed ActiveMQ batch", map[string]interface{}{
+		"messages":     len(event.Messages),
+		"destinations": destinations,
+	})
+	return nil
+}
+
+// HandleRabbitMQ logs every RabbitMQ message with its decoded data and basic properties
+func (h *MQHandler) HandleRabbitMQ(ctx context.Context, event events.RabbitMQEvent) error {
+	// Messages are keyed by "queue::virtual-host"; sort them so the log follows a stable order
+	queues := make([]string, 0, len(event.MessagesByQueue))
+	for queue := range event.MessagesByQueue {
+		queues = append(queues, queue)
+	}
+	sort.Strings(queues)
+
+	counts := make(map[string]int, len(queues))
+	for _, queue := range queues {
+		for _, message := range event.MessagesByQueue[queue] {
+			h.logger.Info("Received RabbitMQ message", map[string]interface{}{
+				"record": rabbitMQEcho(event, queue, message),
+			})
+		}
+		counts[queue] = len(event.MessagesByQueue[queue])
+	}
+
+	h.logger.Info("Processed RabbitMQ batch", map[string]interface{}{
+		"queues": counts,
+	})
+	return nil
+}
+
+// activeMQEcho normalizes an ActiveMQ message
+func activeMQEcho(event events.ActiveMQEvent, message events.ActiveMQMessage) *models.EventEcho {
+	echo := models.NewEventEcho(event.EventSource)
+	echo.DetailType = message.MessageType
+	echo.ID = message.MessageID
+	if message.Timestamp > 0 {
+		echo.Time = time.UnixMilli(message.Timestamp).UTC().Format(time.RFC3339Nano)
+	}
+	echo.Resources = []string{event.EventSourceARN}
+	echo.Attributes["destination"] = message.Destination.PhysicalName
+	echo.Attributes["deliveryMode"] = message.DeliveryMode
+	echo.Attributes["redelivered"] = message.Redelivered
+	echo.Attributes["priority"] = message.Priority
+	echo.Attributes["properties"] = message.Properties
+	if message.CorrelationID != "" {
+		echo.Attributes["correlationId"] = message.CorrelationID
+	}
+	if message.ReplyTo != "" {
+		echo.Attributes["replyTo"] = message.ReplyTo
+	}
+
+	setMQData(echo, message.Data)
+	return echo
+}
+
+// rabbitMQEcho normalizes a RabbitMQ message read from queue
+func rabbitMQEcho(event events.RabbitMQEvent, queue string, message events.RabbitMQMessage) *models.EventEcho {
+	properties := message.BasicProperties
+
+	echo := models.NewEventEcho(event.EventSource)
+	echo.DetailType = queue
+	if properties.MessageID != nil {
+		echo.ID = *properties.MessageID
+	}
+	echo.Time = properties.Timestamp
+	echo.Resources = []string{event.EventSourceARN}
+	echo.Attributes["queue"] = queue
+	echo.Attributes["contentType"] = properties.ContentType
+	echo.Attributes["deliveryMode"] = properties.DeliveryMode
+	echo.Attributes["redelivered"] = message.Redelivered
+	echo.Attributes["headers"] = rabbitMQHeaders(properties.Headers)
+	if properties.CorrelationID != nil {
+		echo.Attributes["correlationId"] = *properties.CorrelationID
+	}
+
+	setMQData(echo, message.Data)
+	return echo
+}
+
+// setMQData decodes the base64 message data
+func setMQData(echo *models.EventEcho, data string) {
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		echo.Body = data
+		echo.ParseError = err.Error()
+		return
+	}
+	setWireData(echo, decoded)
+}
+
+// rabbitMQHeaders converts header values delivered as {"bytes": [...]} into strings
+func rabbitMQHeaders(headers map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{}, len(headers))
+	for name, value := range headers {
+		converted[name] = value
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		numbers, ok := object["bytes"].([]interface{})
+		if !ok || len(object) != 1 {
+			continue
+		}
+
+		data := make([]byte, 0, len(numbers))
+		for _, number := range numbers {
+			n, ok := number.(float64)
+			if !ok {
+				data = nil
+				break
+			}
+			data = append(data, byte(int(n)))
+		}
+		if data != nil {
+			converted[name] = printable(data)
+		}
+	}
+	return converted
+}
@@ -20,13 +20,16 @@ type NonProxyRequest struct {
 
 // NonProxyHandler handles AWS Lambda non-proxy requests
 type NonProxyHandler struct {
-	logger *logger.Logger
+	logger     *logger.Logger
+	inspectors inspectors
 }
 
 // NewNonProxyHandler creates a new non-proxy Lambda handler instance
 func NewNonProxyHandler() *NonProxyHandler {
+	l := logger.New()
 	return &NonProxyHandler{
-		logger: logger.New(),
+		logger:     l,
+		inspectors: newInspectors(l),
 	}
 }
 
@@ -60,6 +63,16 @@ func (h *NonProxyHandler) HandleRequest(ctx context.Context, request NonProxyReq
 		"message":     echoResponse.Message,
 		"processedAt": echoResponse.ProcessedAt,
 	}
+	h.inspectors.annotate(echoResponse, request.Path)
+	if echoResponse.Token != nil {
+		responseMap["token"] = echoResponse.Token
+	}
+	if echoResponse.Webhook != nil {
+		responseMap["webhook"] = echoResponse.Webhook
+	}
+	if echoResponse.SigV4 != nil {
+		responseMap["sigv4"] = echoResponse.SigV4
+	}
 
 	// Convert response to JSON for logging
 	responseJSON, err := json.Marshal(responseMap)
@@ -94,13 +107,16 @@ func (h *NonProxyHandler) isMethodAllowed(method string) bool {
 // parseRequest extracts request information from non-proxy request
 func (h *NonProxyHandler) parseRequest(request *NonProxyRequest) *models.EchoRequest {
 	// Create the echo request
-	return models.NewEchoRequest(
+	echoRequest := models.NewEchoRequest(
 		request.HTTPMethod,
 		request.Path,
 		request.Headers,
 		request.QueryStringParameters,
 		request.Body,
 	)
+	decodeBody(echoRequest)
+
+	return echoRequest
 }
 
 // createErrorResponse creates a standardized error response
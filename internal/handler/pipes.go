@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"echo-api/internal/models"
+	"echo-api/pkg/logger"
+)
+
+// pipesPayloadFields hold the payload of the source record in a Pipes event, in lookup order
+var pipesPayloadFields = []string{"body", "data", "detail", "dynamodb", "value", "message"}
+
+// PipesHandler echoes the batches EventBridge Pipes delivers to a Lambda target or enrichment
+type PipesHandler struct {
+	logger *logger.Logger
+}
+
+// NewPipesHandler creates a new Pipes handler instance
+func NewPipesHandler() *PipesHandler {
+	return &PipesHandler{
+		logger: logger.New(),
+	}
+}
+
+// HandleRequest logs each event and returns the batch unchanged, so the handler can also sit in the
+// enrichment step; an event with "echo-fail": true in its payload fails the batch
+func (h *PipesHandler) HandleRequest(ctx context.Context, batch []map[string]interface{}) ([]map[string]interface{}, error) {
+	failed := 0
+	for index, event := range batch {
+		echo := pipesEcho(event)
+		fail := failRequested(echo.Detail)
+
+		h.logger.Info("Received Pipes event", map[string]interface{}{
+			"index":  index,
+			"record": echo,
+			"failed": fail,
+		})
+		if fail {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return nil, fmt.Errorf("%s requested for %d of %d events", failDirective, failed, len(batch))
+	}
+	return batch, nil
+}
+
+// pipesEcho normalizes a Pipes event, whose shape depends on the pipe source, into an echo record
+func pipesEcho(event map[string]interface{}) *models.EventEcho {
+	source := firstString(event, "eventSource", "source")
+	echo := models.NewEventEcho(source)
+	echo.DetailType = firstString(event, "detail-type", "eventName")
+	echo.ID = firstString(event, "messageId", "eventID", "id")
+	echo.Time = firstString(event, "time")
+	if arn := firstString(event, "eventSourceARN", "eventSourceArn"); arn != "" {
+		echo.Resources = []string{arn}
+	} else if resources, ok := event["resources"].([]interface{}); ok {
+		for _, resource := range resources {
+			if value, ok := resource.(string); ok {
+				echo.Resources = append(echo.Resources, value)
+			}
+		}
+	}
+
+	payloadField := ""
+	for _, field := range pipesPayloadFields {
+		if _, ok := event[field]; ok {
+			payloadField = field
+			break
+		}
+	}
+	for key, value := range event {
+		if key != payloadField {
+			echo.Attributes[key] = value
+		}
+	}
+
+	switch payload := event[payloadField].(type) {
+	case nil:
+	case string:
+		// Kinesis and Kafka sources deliver base64 encoded data
+		if payloadField == "data" || payloadField == "value" {
+			if decoded, err := base64.StdEncoding.DecodeString(payload); err == nil {
+				parseEventBody(echo, decoded)
+				break
+			}
+		}
+		parseEventBody(echo, []byte(payload))
+	default:
+		// EventBridge and DynamoDB sources deliver the payload as JSON already
+		echo.Detail = payload
+	}
+	return echo
+}
+
+// firstString returns the first of the named fields that holds a non-empty string
+func firstString(event map[string]interface{}, names ...string) string {
+	for _, name := range names {
+		if value, ok := event[name].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"echo-api/internal/byterange"
+	"echo-api/internal/cache"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const (
+	// maxRangeSize caps the size of the /range/{n} representation
+	maxRangeSize = 100 * 1024
+	// rangeBoundary separates multipart/byteranges parts; it cannot occur in the generated bytes
+	rangeBoundary = "echo-api-byteranges"
+	// rangeContentType is the media type of the generated bytes
+	rangeContentType = "application/octet-stream"
+)
+
+// handleRange serves /range/{n}: n deterministic bytes that honor Range and If-Range
+func (h *LambdaHandler) handleRange(ctx context.Context, r *routeRequest) (events.APIGatewayProxyResponse, error) {
+	size, err := strconv.Atoi(r.params["n"])
+	if err != nil || size < 0 || size > maxRangeSize {
+		return h.createErrorResponse(http.StatusBadRequest, "Bad Request", "n must be an integer between 0 and "+strconv.Itoa(maxRangeSize))
+	}
+	data := rangeBytes(size)
+
+	// The bytes only depend on n, so the tag is stable and strong
+	resource := cache.Resource{
+		ETag:         cache.ETag{Value: "range-" + strconv.Itoa(size)},
+		LastModified: h.now().UTC().Truncate(24 * time.Hour),
+	}
+	validators := []string{
+		"Accept-Ranges", "bytes",
+		"ETag", resource.ETag.String(),
+		"Last-Modified", resource.LastModified.Format(http.TimeFormat),
+	}
+
+	switch cache.Evaluate(r.proxy.HTTPMethod, r.echo.Header, resource) {
+	case http.StatusNotModified:
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusNotModified,
+			Headers:    responseHeaders(rangeContentType, validators...),
+		}, nil
+	case http.StatusPreconditionFailed:
+		return h.createErrorResponse(http.StatusPreconditionFailed, "Precondition Failed", "The resource does not match the request preconditions")
+	}
+
+	header := r.echo.Header("Range")
+	if header == "" || r.proxy.HTTPMethod != http.MethodGet || !cache.RangeApplies(r.echo.Header("If-Range"), resource) {
+		return rangeResponse(http.StatusOK, rangeContentType, data, validators), nil
+	}
+
+	ranges, err := byterange.Parse(header, int64(size))
+	if errors.Is(err, byterange.ErrUnsatisfiable) {
+		h.logger.Warn("Range not satisfiable", map[string]interface{}{
+			"range": header,
+			"size":  size,
+		})
+		response, err := h.createErrorResponse(http.StatusRequestedRangeNotSatisfiable, "Range Not Satisfiable", "No requested range overlaps the "+strconv.Itoa(size)+" byte representation")
+		response.Headers["Content-Range"] = byterange.UnsatisfiedContentRange(int64(size))
+		response.Headers["Accept-Ranges"] = "bytes"
+		return response, err
+	}
+	if err != nil {
+		// Malformed Range headers are ignored and the full representation is served
+		return rangeResponse(http.StatusOK, rangeContentType, data, validators), nil
+	}
+
+	h.logger.Info("Serving partial content", map[string]interface{}{
+		"range":  header,
+		"size":   size,
+		"ranges": len(ranges),
+	})
+
+	if len(ranges) == 1 {
+		part := ranges[0]
+		validators = append(validators, "Content-Range", part.ContentRange(int64(size)))
+		return rangeResponse(http.StatusPartialContent, rangeContentType, data[part.Start:part.End+1], validators), nil
+	}
+	body := byterange.Multipart(data, ranges, rangeContentType, rangeBoundary)
+	return rangeResponse(http.StatusPartialContent, "multipart/byteranges; boundary="+rangeBoundary, body, validators), nil
+}
+
+// rangeResponse builds a base64 encoded binary response
+func rangeResponse(statusCode int, contentType string, body []byte, headers []string) events.APIGatewayProxyResponse {
+	return events.APIGatewayProxyResponse{
+		StatusCode:      statusCode,
+		Headers:         responseHeaders(contentType, headers...),
+		Body:            base64.StdEncoding.EncodeToString(body),
+		IsBase64Encoded: true,
+	}
+}
+
+// rangeBytes generates n bytes cycling through the lowercase alphabet
+func rangeBytes(n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = 'a' + byte(i%26)
+	}
+	return data
+}
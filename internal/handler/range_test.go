@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestHandleRequest_Range(t *testing.T) {
+	handler := NewLambdaHandler()
+	handler.now = func() time.Time { return time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC) }
+	ctx := context.Background()
+
+	rangeRequest := func(headers map[string]string) events.APIGatewayProxyResponse {
+		response, err := handler.HandleRequest(ctx, events.APIGatewayProxyRequest{
+			HTTPMethod: "GET",
+			Path:       "/range/52",
+			Headers:    headers,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return response
+	}
+	decode := func(response events.APIGatewayProxyResponse) string {
+		body, err := base64.StdEncoding.DecodeString(response.Body)
+		if err != nil {
+			t.Fatalf("Expected base64 body: %v", err)
+		}
+		return string(body)
+	}
+
+	full := rangeRequest(nil)
+	if full.StatusCode != http.StatusOK || decode(full) != strings.Repeat("abcdefghijklmnopqrstuvwxyz", 2) {
+		t.Errorf("Expected full representation, got %d %q", full.StatusCode, decode(full))
+	}
+	if full.Headers["Accept-Ranges"] != "bytes" || full.Headers["ETag"] != `"range-52"` {
+		t.Errorf("Expected range validators, got %v", full.Headers)
+	}
+
+	// The full representation stays identity so If-Range resumes join bytes of one encoding
+	handler.minCompressSize = 0
+	if compressed := rangeRequest(map[string]string{"Accept-Encoding": "gzip"}); compressed.Headers["Content-Encoding"] != "" {
+		t.Errorf("Expected an uncompressed full representation, got %v", compressed.Headers)
+	}
+
+	single := rangeRequest(map[string]string{"Range": "bytes=26-28", "Accept-Encoding": "gzip"})
+	if single.StatusCode != http.StatusPartialContent || decode(single) != "abc" {
+		t.Errorf("Expected 206 with abc, got %d %q", single.StatusCode, decode(single))
+	}
+	if single.Headers["Content-Range"] != "bytes 26-28/52" || single.Headers["Content-Encoding"] != "" {
+		t.Errorf("Unexpected partial headers %v", single.Headers)
+	}
+
+	multi := rangeRequest(map[string]string{"Range": "bytes=0-1,-2"})
+	if multi.StatusCode != http.StatusPartialContent || !strings.HasPrefix(multi.Headers["Content-Type"], "multipart/byteranges; boundary=") {
+		t.Errorf("Expected multipart 206, got %d %q", multi.StatusCode, multi.Headers["Content-Type"])
+	}
+	if body := decode(multi); !strings.Contains(body, "bytes 0-1/52\r\n\r\nab\r\n") || !strings.Contains(body, "bytes 50-51/52\r\n\r\nyz\r\n") {
+		t.Errorf("Unexpected multipart body %q", body)
+	}
+
+	unsatisfiable := rangeRequest(map[string]string{"Range": "bytes=52-"})
+	if unsatisfiable.StatusCode != http.StatusRequestedRangeNotSatisfiable || unsatisfiable.Headers["Content-Range"] != "bytes */52" {
+		t.Errorf("Expected 416 with bytes */52, got %d %v", unsatisfiable.StatusCode, unsatisfiable.Headers)
+	}
+
+	stale := rangeRequest(map[string]string{"Range": "bytes=0-1", "If-Range": `"range-10"`})
+	if stale.StatusCode != http.StatusOK || len(decode(stale)) != 52 {
+		t.Errorf("Expected full 200 for stale If-Range, got %d", stale.StatusCode)
+	}
+
+	fresh := rangeRequest(map[string]string{"Range": "bytes=0-1", "If-Range": "Tue, 02 Jan 2024 00:00:00 GMT"})
+	if fresh.StatusCode != http.StatusPartialContent {
+		t.Errorf("Expected 206 for matching If-Range date, got %d", fresh.StatusCode)
+	}
+
+	invalid, _ := handler.HandleRequest(ctx, events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: "/range/huge"})
+	if invalid.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid size, got %d", invalid.StatusCode)
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"echo-api/internal/compress"
+	"echo-api/internal/models"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// routeRequest carries a parsed request to a route handler
+type routeRequest struct {
+	proxy  *events.APIGatewayProxyRequest
+	echo   *models.EchoRequest
+	params map[string]string
+	// functionURL is set when the request came through a Lambda Function URL
+	functionURL *models.FunctionURLInfo
+}
+
+// routeHandler serves a single route
+type routeHandler func(h *LambdaHandler, ctx context.Context, r *routeRequest) (events.APIGatewayProxyResponse, error)
+
+// route binds a path pattern such as /cache/{seconds} to its handler
+type route struct {
+	pattern string
+	handle  routeHandler
+}
+
+// routes lists the endpoints with special behavior; any other path is echoed
+var routes = []route{
+	{"/gzip", compressedEcho(compress.Gzip)},
+	{"/deflate", compressedEcho(compress.Deflate)},
+	{"/brotli", compressedEcho(compress.Brotli)},
+	{"/cache", (*LambdaHandler).handleCache},
+	{"/cache/{seconds}", (*LambdaHandler).handleCache},
+	{"/etag/{etag}", (*LambdaHandler).handleETag},
+	{"/response-headers", (*LambdaHandler).handleResponseHeaders},
+	{"/range/{n}", (*LambdaHandler).handleRange},
+	{"/_history", (*LambdaHandler).handleHistory},
+	{"/_history/clear", (*LambdaHandler).handleHistoryClear},
+	{"/_history/{id}", (*LambdaHandler).handleHistoryRecord},
+	{"/bins", (*LambdaHandler).handleBinCreate},
+	{"/bins/{token}", (*LambdaHandler).handleBin},
+	{"/b/{token}", (*LambdaHandler).handleBinCapture},
+	{"/b/{token}/*", (*LambdaHandler).handleBinCapture},
+}
+
+// matchRoute finds the handler for path, falling back to the echo
+func matchRoute(path string) (routeHandler, map[string]string) {
+	for _, r := range routes {
+		if params, ok := matchPattern(r.pattern, path); ok {
+			return r.handle, params
+		}
+	}
+	return (*LambdaHandler).handleEcho, nil
+}
+
+// matchPattern matches path against pattern segment by segment, capturing {name} segments;
+// a final * segment captures the remaining one or more segments as the parameter "*"
+func matchPattern(pattern, path string) (map[string]string, bool) {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+
+	params := make(map[string]string)
+	if last := len(patternSegments) - 1; patternSegments[last] == "*" {
+		if len(pathSegments) <= last {
+			return nil, false
+		}
+		params["*"] = strings.Join(pathSegments[last:], "/")
+		patternSegments, pathSegments = patternSegments[:last], pathSegments[:last]
+	}
+	if len(patternSegments) != len(pathSegments) {
+		return nil, false
+	}
+
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if pathSegments[i] == "" {
+				return nil, false
+			}
+			params[segment[1:len(segment)-1]] = pathSegments[i]
+			continue
+		}
+		if segment != pathSegments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
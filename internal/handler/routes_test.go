@@ -0,0 +1,35 @@
+package handler
+
+import "testing"
+
+func TestMatchPattern(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		path    string
+		ok      bool
+		params  map[string]string
+	}{
+		{"/gzip", "/gzip", true, map[string]string{}},
+		{"/gzip", "/gzip/", true, map[string]string{}},
+		{"/gzip", "/gzip/extra", false, nil},
+		{"/cache/{seconds}", "/cache/60", true, map[string]string{"seconds": "60"}},
+		{"/cache/{seconds}", "/cache", false, nil},
+		{"/cache/{seconds}", "/cache//", false, nil},
+		{"/b/{token}/*", "/b/abc/hooks/github", true, map[string]string{"token": "abc", "*": "hooks/github"}},
+		{"/b/{token}/*", "/b/abc", false, nil},
+		{"/b/{token}/*", "/b/abc/", false, nil},
+	}
+
+	for _, tc := range testCases {
+		params, ok := matchPattern(tc.pattern, tc.path)
+		if ok != tc.ok {
+			t.Errorf("For %s against %s, expected match %v, got %v", tc.path, tc.pattern, tc.ok, ok)
+			continue
+		}
+		for key, value := range tc.params {
+			if params[key] != value {
+				t.Errorf("For %s against %s, expected %s=%s, got %s", tc.path, tc.pattern, key, value, params[key])
+			}
+		}
+	}
+}
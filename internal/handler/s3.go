@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/internal/s3"
+	"echo-api/internal/sigv4"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// S3Handler echoes S3 event notifications, optionally with the object's head metadata
+type S3Handler struct {
+	logger *logger.Logger
+	client s3.HeadObjecter
+}
+
+// NewS3Handler creates a new S3 handler; S3_HEAD_OBJECT=true enables reading object metadata
+// with the function's credentials
+func NewS3Handler() *S3Handler {
+	if !isTrue(os.Getenv("S3_HEAD_OBJECT")) {
+		return NewS3HandlerWithClient(nil)
+	}
+	signer := sigv4.NewSigner(sigv4.AWSCredentialsFromEnv(), os.Getenv("AWS_REGION"), "s3")
+	return NewS3HandlerWithClient(s3.NewClient(signer))
+}
+
+// NewS3HandlerWithClient creates a new S3 handler that reads metadata through client; nil disables it
+func NewS3HandlerWithClient(client s3.HeadObjecter) *S3Handler {
+	return &S3Handler{
+		logger: logger.New(),
+		client: client,
+	}
+}
+
+// HandleRequest logs every record; metadata lookup failures are logged with the record rather than failing it
+func (h *S3Handler) HandleRequest(ctx context.Context, event events.S3Event) error {
+	for _, record := range event.Records {
+		echo := s3Echo(record)
+
+		// Removed objects have nothing left to read
+		if h.client != nil && !strings.HasPrefix(record.EventName, "ObjectRemoved") {
+			metadata, err := h.client.HeadObject(ctx, record.S3.Bucket.Name, s3Key(record.S3.Object), record.S3.Object.VersionID)
+			if err != nil {
+				echo.Attributes["headError"] = err.Error()
+			} else {
+				echo.Attributes["head"] = metadata
+			}
+		}
+
+		h.logger.Info("Received S3 event", map[string]interface{}{
+			"record": echo,
+		})
+	}
+
+	h.logger.Info("Processed S3 event", map[string]interface{}{
+		"records": len(event.Records),
+	})
+	return nil
+}
+
+// s3Echo normalizes an S3 event record into an echo record
+func s3Echo(record events.S3EventRecord) *models.EventEcho {
+	object := record.S3.Object
+
+	echo := models.NewEventEcho(record.EventSource)
+	echo.DetailType = record.EventName
+	echo.ID = record.ResponseElements["x-amz-request-id"]
+	echo.Time = record.EventTime.UTC().Format(time.RFC3339Nano)
+	echo.Resources = []string{record.S3.Bucket.Arn}
+	echo.Attributes["bucket"] = record.S3.Bucket.Name
+	echo.Attributes["key"] = s3Key(object)
+	echo.Attributes["rawKey"] = object.Key
+	echo.Attributes["size"] = object.Size
+	echo.Attributes["eTag"] = object.ETag
+	echo.Attributes["sequencer"] = object.Sequencer
+	echo.Attributes["awsRegion"] = record.AWSRegion
+	echo.Attributes["configurationId"] = record.S3.ConfigurationID
+	echo.Attributes["principalId"] = record.PrincipalID.PrincipalID
+	echo.Attributes["sourceIPAddress"] = record.RequestParameters.SourceIPAddress
+	if object.VersionID != "" {
+		echo.Attributes["versionId"] = object.VersionID
+	}
+	return echo
+}
+
+// s3Key returns the object key without the URL encoding S3 applies in notifications
+func s3Key(object events.S3Object) string {
+	if object.URLDecodedKey != "" {
+		return object.URLDecodedKey
+	}
+	if key, err := url.QueryUnescape(object.Key); err == nil {
+		return key
+	}
+	return object.Key
+}
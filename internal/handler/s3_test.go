@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"echo-api/internal/s3"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const s3EventJSON = `{"Records": [{
+	"eventVersion": "2.1", "eventSource": "aws:s3", "awsRegion": "us-east-1",
+	"eventTime": "2024-01-02T03:04:05.678Z", "eventName": "ObjectCreated:Put",
+	"responseElements": {"x-amz-request-id": "REQ1"},
+	"s3": {
+		"configurationId": "echo",
+		"bucket": {"name": "uploads", "arn": "arn:aws:s3:::uploads"},
+		"object": {"key": "photos/my+photo%281%29.png", "size": 42, "eTag": "abc", "sequencer": "0055AED6DCD90281E5"}
+	}
+}]}`
+
+func TestS3Echo(t *testing.T) {
+	var event events.S3Event
+	if err := json.Unmarshal([]byte(s3EventJSON), &event); err != nil {
+		t.Fatalf("Failed to unmarshal event: %v", err)
+	}
+
+	echo := s3Echo(event.Records[0])
+	if echo.Attributes["key"] != "photos/my photo(1).png" || echo.Attributes["rawKey"] != "photos/my+photo%281%29.png" {
+		t.Errorf("Expected the URL-decoded key, got %v", echo.Attributes["key"])
+	}
+	if echo.DetailType != "ObjectCreated:Put" || echo.ID != "REQ1" || echo.Attributes["size"] != int64(42) || echo.Attributes["sequencer"] != "0055AED6DCD90281E5" {
+		t.Errorf("Unexpected echo %+v", echo)
+	}
+
+	// Records built in code have no pre-decoded key
+	if key := s3Key(events.S3Object{Key: "a+b%2Bc"}); key != "a b+c" {
+		t.Errorf("Expected a b+c, got %q", key)
+	}
+}
+
+// recordingHeadObjecter records the keys it is asked for
+type recordingHeadObjecter struct {
+	s3.HeadObjecter
+	keys []string
+}
+
+func (r *recordingHeadObjecter) HeadObject(ctx context.Context, bucket, key, versionID string) (*s3.ObjectMetadata, error) {
+	r.keys = append(r.keys, bucket+"/"+key)
+	return r.HeadObjecter.HeadObject(ctx, bucket, key, versionID)
+}
+
+func TestS3Handler_HeadObject(t *testing.T) {
+	var event events.S3Event
+	json.Unmarshal([]byte(s3EventJSON), &event)
+
+	store := s3.NewMemoryStore()
+	store.Put("uploads", "photos/my photo(1).png", &s3.ObjectMetadata{ContentType: "image/png", ContentLength: 42})
+	client := &recordingHeadObjecter{HeadObjecter: store}
+
+	if err := NewS3HandlerWithClient(client).HandleRequest(context.Background(), event); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(client.keys) != 1 || client.keys[0] != "uploads/photos/my photo(1).png" {
+		t.Errorf("Expected a head request for the decoded key, got %v", client.keys)
+	}
+
+	// Removed objects are not looked up
+	event.Records[0].EventName = "ObjectRemoved:Delete"
+	NewS3HandlerWithClient(client).HandleRequest(context.Background(), event)
+	if len(client.keys) != 1 {
+		t.Errorf("Expected no head request for a removed object, got %v", client.keys)
+	}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// SESHandler echoes emails received through an SES receipt rule
+type SESHandler struct {
+	logger *logger.Logger
+}
+
+// NewSESHandler creates a new SES handler instance
+func NewSESHandler() *SESHandler {
+	return &SESHandler{
+		logger: logger.New(),
+	}
+}
+
+// HandleRequest logs the mail and receipt of every record; synchronous invocations let the rule set continue
+func (h *SESHandler) HandleRequest(ctx context.Context, event events.SimpleEmailEvent) (events.SimpleEmailDisposition, error) {
+	for _, record := range event.Records {
+		h.logger.Info("Received email", map[string]interface{}{
+			"record": sesEcho(record),
+		})
+	}
+	return events.SimpleEmailDisposition{Disposition: events.SimpleEmailContinue}, nil
+}
+
+// sesEcho normalizes an SES record, keeping the receipt verdicts together
+func sesEcho(record events.SimpleEmailRecord) *models.EventEcho {
+	mail := record.SES.Mail
+	receipt := record.SES.Receipt
+
+	echo := models.NewEventEcho(record.EventSource)
+	echo.DetailType = mail.CommonHeaders.Subject
+	echo.ID = mail.MessageID
+	echo.Time = mail.Timestamp.UTC().Format(time.RFC3339Nano)
+	if receipt.Action.FunctionARN != "" {
+		echo.Resources = []string{receipt.Action.FunctionARN}
+	}
+	echo.Attributes["source"] = mail.Source
+	echo.Attributes["destination"] = mail.Destination
+	echo.Attributes["commonHeaders"] = mail.CommonHeaders
+	echo.Attributes["headers"] = mail.Headers
+	echo.Attributes["headersTruncated"] = mail.HeadersTruncated
+	echo.Attributes["recipients"] = receipt.Recipients
+	echo.Attributes["verdicts"] = map[string]string{
+		"spf":   receipt.SPFVerdict.Status,
+		"dkim":  receipt.DKIMVerdict.Status,
+		"dmarc": receipt.DMARCVerdict.Status,
+		"spam":  receipt.SpamVerdict.Status,
+		"virus": receipt.VirusVerdict.Status,
+	}
+	if receipt.DMARCPolicy != "" {
+		echo.Attributes["dmarcPolicy"] = receipt.DMARCPolicy
+	}
+	echo.Attributes["action"] = receipt.Action
+	echo.Attributes["processingTimeMillis"] = receipt.ProcessingTimeMillis
+	return echo
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestSESEcho(t *testing.T) {
+	var event events.SimpleEmailEvent
+	if err := json.Unmarshal([]byte(`{"Records": [{"eventSource": "aws:ses", "eventVersion": "1.0", "ses": {
+		"mail": {
+			"timestamp": "2024-01-02T03:04:05.000Z", "source": "alice@example.com", "messageId": "m1",
+			"destination": ["echo@example.org"], "headersTruncated": false,
+			"headers": [{"name": "From", "value": "alice@example.com"}],
+			"commonHeaders": {"from": ["alice@example.com"], "to": ["echo@example.org"], "subject": "hello"}
+		},
+		"receipt": {
+			"recipients": ["echo@example.org"], "processingTimeMillis": 120,
+			"spamVerdict": {"status": "PASS"}, "virusVerdict": {"status": "PASS"}, "spfVerdict": {"status": "PASS"},
+			"dkimVerdict": {"status": "GRAY"}, "dmarcVerdict": {"status": "FAIL"}, "dmarcPolicy": "reject",
+			"action": {"type": "Lambda", "functionArn": "arn:aws:lambda:us-east-1:123456789012:function:echo", "invocationType": "Event"}
+		}
+	}}]}`), &event); err != nil {
+		t.Fatalf("Failed to unmarshal event: %v", err)
+	}
+
+	echo := sesEcho(event.Records[0])
+	if echo.ID != "m1" || echo.DetailType != "hello" || echo.Time != "2024-01-02T03:04:05Z" {
+		t.Errorf("Unexpected echo %+v", echo)
+	}
+	verdicts := echo.Attributes["verdicts"].(map[string]string)
+	if verdicts["dkim"] != "GRAY" || verdicts["dmarc"] != "FAIL" || echo.Attributes["dmarcPolicy"] != "reject" {
+		t.Errorf("Unexpected verdicts %v", verdicts)
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// SNSHandler echoes SNS notifications
+type SNSHandler struct {
+	logger *logger.Logger
+}
+
+// NewSNSHandler creates a new SNS handler instance
+func NewSNSHandler() *SNSHandler {
+	return &SNSHandler{
+		logger: logger.New(),
+	}
+}
+
+// HandleRequest logs every notification; a notification with the echo-fail message attribute fails
+// the invocation so SNS retries and dead-letter queues can be exercised
+func (h *SNSHandler) HandleRequest(ctx context.Context, event events.SNSEvent) error {
+	var failed []string
+	for _, record := range event.Records {
+		echo := snsEcho(record)
+		fail := isTrue(snsMessageAttribute(record.SNS, failDirective))
+
+		h.logger.Info("Received SNS notification", map[string]interface{}{
+			"record": echo,
+			"failed": fail,
+		})
+		if fail {
+			failed = append(failed, record.SNS.MessageID)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%s requested for messages %s", failDirective, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// snsEcho normalizes an SNS notification into an echo record
+func snsEcho(record events.SNSEventRecord) *models.EventEcho {
+	echo := models.NewEventEcho(record.EventSource)
+	echo.DetailType = record.SNS.Type
+	echo.ID = record.SNS.MessageID
+	echo.Time = record.SNS.Timestamp.UTC().Format(time.RFC3339Nano)
+	echo.Resources = []string{record.SNS.TopicArn, record.EventSubscriptionArn}
+	echo.Attributes["subject"] = record.SNS.Subject
+	echo.Attributes["messageAttributes"] = record.SNS.MessageAttributes
+	echo.Attributes["signatureVersion"] = record.SNS.SignatureVersion
+
+	parseEventBody(echo, []byte(record.SNS.Message))
+	return echo
+}
+
+// snsMessageAttribute returns the value of a message attribute, which SNS delivers as {"Type": ..., "Value": ...}
+func snsMessageAttribute(entity events.SNSEntity, name string) string {
+	for key, attribute := range entity.MessageAttributes {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		if fields, ok := attribute.(map[string]interface{}); ok {
+			value, _ := fields["Value"].(string)
+			return value
+		}
+	}
+	return ""
+}
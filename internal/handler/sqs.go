@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"echo-api/internal/models"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// SQSHandler echoes SQS messages and reports the ones marked with echo-fail as batch item failures
+type SQSHandler struct {
+	logger *logger.Logger
+}
+
+// NewSQSHandler creates a new SQS handler instance
+func NewSQSHandler() *SQSHandler {
+	return &SQSHandler{
+		logger: logger.New(),
+	}
+}
+
+// HandleRequest logs every message and returns the failed message ids; the event source mapping
+// needs ReportBatchItemFailures enabled for the partial failures to be honored
+func (h *SQSHandler) HandleRequest(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+	response := events.SQSEventResponse{BatchItemFailures: []events.SQSBatchItemFailure{}}
+
+	failFIFO := false
+	for _, record := range event.Records {
+		echo := sqsEcho(record)
+		failed := isTrue(messageAttributeString(record, failDirective))
+
+		// A FIFO queue must not see later messages of a group succeed after an earlier one failed
+		if failFIFO {
+			failed = true
+		} else if failed && strings.HasSuffix(record.EventSourceARN, ".fifo") {
+			failFIFO = true
+		}
+
+		h.logger.Info("Received SQS message", map[string]interface{}{
+			"record": echo,
+			"failed": failed,
+		})
+		if failed {
+			response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: record.MessageId})
+		}
+	}
+
+	h.logger.Info("Processed SQS batch", map[string]interface{}{
+		"records":  len(event.Records),
+		"failures": len(response.BatchItemFailures),
+	})
+	return response, nil
+}
+
+// sqsEcho normalizes an SQS message into an echo record
+func sqsEcho(record events.SQSMessage) *models.EventEcho {
+	echo := models.NewEventEcho(record.EventSource)
+	echo.ID = record.MessageId
+	echo.Time = epochMillis(record.Attributes["SentTimestamp"])
+	echo.Resources = []string{record.EventSourceARN}
+	echo.Attributes["attributes"] = record.Attributes
+	echo.Attributes["awsRegion"] = record.AWSRegion
+
+	messageAttributes := make(map[string]interface{}, len(record.MessageAttributes))
+	for name, attribute := range record.MessageAttributes {
+		value := map[string]interface{}{"dataType": attribute.DataType}
+		if attribute.StringValue != nil {
+			value["stringValue"] = *attribute.StringValue
+		}
+		if attribute.BinaryValue != nil {
+			value["binaryValue"] = attribute.BinaryValue
+		}
+		messageAttributes[name] = value
+	}
+	echo.Attributes["messageAttributes"] = messageAttributes
+
+	parseEventBody(echo, []byte(record.Body))
+	return echo
+}
+
+// messageAttributeString returns the string value of a message attribute, matching the name case-insensitively
+func messageAttributeString(record events.SQSMessage, name string) string {
+	for key, attribute := range record.MessageAttributes {
+		if strings.EqualFold(key, name) && attribute.StringValue != nil {
+			return *attribute.StringValue
+		}
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func sqsMessage(id, body, fail string) events.SQSMessage {
+	message := events.SQSMessage{
+		MessageId:      id,
+		Body:           body,
+		EventSource:    "aws:sqs",
+		EventSourceARN: "arn:aws:sqs:us-east-1:123456789012:echo",
+		Attributes:     map[string]string{"SentTimestamp": "1700000000000"},
+	}
+	if fail != "" {
+		message.MessageAttributes = map[string]events.SQSMessageAttribute{
+			"echo-fail": {StringValue: &fail, DataType: "String"},
+		}
+	}
+	return message
+}
+
+func TestSQSHandler_BatchItemFailures(t *testing.T) {
+	handler := NewSQSHandler()
+
+	response, err := handler.HandleRequest(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+		sqsMessage("m1", `{"a":1}`, ""),
+		sqsMessage("m2", "plain", "true"),
+		sqsMessage("m3", "plain", "false"),
+	}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.BatchItemFailures) != 1 || response.BatchItemFailures[0].ItemIdentifier != "m2" {
+		t.Errorf("Expected only m2 to fail, got %+v", response.BatchItemFailures)
+	}
+}
+
+func TestSQSHandler_FIFOFailsRemainingMessages(t *testing.T) {
+	handler := NewSQSHandler()
+
+	records := []events.SQSMessage{
+		sqsMessage("m1", "a", ""),
+		sqsMessage("m2", "b", "true"),
+		sqsMessage("m3", "c", ""),
+	}
+	for i := range records {
+		records[i].EventSourceARN = "arn:aws:sqs:us-east-1:123456789012:echo.fifo"
+	}
+
+	response, _ := handler.HandleRequest(context.Background(), events.SQSEvent{Records: records})
+	if len(response.BatchItemFailures) != 2 || response.BatchItemFailures[1].ItemIdentifier != "m3" {
+		t.Errorf("Expected m2 and m3 to fail, got %+v", response.BatchItemFailures)
+	}
+}
+
+func TestSQSEcho(t *testing.T) {
+	echo := sqsEcho(sqsMessage("m1", `{"a":1}`, "true"))
+
+	if echo.Source != "aws:sqs" || echo.ID != "m1" || echo.Time != "2023-11-14T22:13:20Z" {
+		t.Errorf("Unexpected echo %+v", echo)
+	}
+	if detail, ok := echo.Detail.(map[string]interface{}); !ok || detail["a"] == nil {
+		t.Errorf("Expected parsed JSON detail, got %v", echo.Detail)
+	}
+	messageAttributes := echo.Attributes["messageAttributes"].(map[string]interface{})
+	if messageAttributes["echo-fail"].(map[string]interface{})["stringValue"] != "true" {
+		t.Errorf("Expected message attributes in the echo, got %v", messageAttributes)
+	}
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/internal/stream"
+)
+
+const (
+	// minSSEInterval bounds the event rate of the open-ended /sse stream
+	minSSEInterval = 100 * time.Millisecond
+	// maxSSEEventID bounds the Last-Event-ID a stream resumes after, keeping the sequence arithmetic in range
+	maxSSEEventID = 1 << 30
+)
+
+// handleSSE serves /sse and /sse/{n} as a text/event-stream of request echoes; /sse keeps sending
+// until the stream duration limit so clients exercise reconnection
+func (h *LambdaHandler) handleSSE(ctx context.Context, r *routeRequest) *stream.Response {
+	query := r.echo.QueryParams
+
+	interval, err := streamSeconds(query["interval"], time.Second)
+	if err != nil {
+		return h.errorStream(http.StatusBadRequest, "Bad Request", "interval must be a number of seconds")
+	}
+
+	var retry time.Duration
+	if value := query["retry"]; value != "" {
+		milliseconds, err := strconv.Atoi(value)
+		if err != nil || milliseconds < 0 {
+			return h.errorStream(http.StatusBadRequest, "Bad Request", "retry must be a non-negative number of milliseconds")
+		}
+		retry = time.Duration(milliseconds) * time.Millisecond
+	}
+
+	// EventSource sends Last-Event-ID when it reconnects; resume after it
+	first := 0
+	lastEventID := r.echo.Header("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = query["lastEventId"]
+	}
+	if id, err := strconv.Atoi(lastEventID); err == nil && id >= 0 {
+		if id > maxSSEEventID {
+			return h.errorStream(http.StatusBadRequest, "Bad Request", fmt.Sprintf("Last-Event-ID must be at most %d", maxSSEEventID))
+		}
+		first = id + 1
+	}
+
+	var last int
+	if value, ok := r.params["n"]; ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 || n > maxStreamLines {
+			return h.errorStream(http.StatusBadRequest, "Bad Request", fmt.Sprintf("n must be an integer between 0 and %d", maxStreamLines))
+		}
+		last = n
+		if first < last && interval*time.Duration(last-first-1) > maxStreamDuration {
+			return h.errorStream(http.StatusBadRequest, "Bad Request", fmt.Sprintf("interval must be seconds and the stream must finish within %v", maxStreamDuration))
+		}
+	} else {
+		if interval < minSSEInterval {
+			return h.errorStream(http.StatusBadRequest, "Bad Request", fmt.Sprintf("interval must be at least %v for an open-ended stream", minSSEInterval))
+		}
+		last = first + int(maxStreamDuration/interval) + 1
+	}
+
+	// 204 tells EventSource the stream is over and it should stop reconnecting
+	if first >= last {
+		return stream.Buffered(http.StatusNoContent, responseHeaders("text/event-stream"), nil)
+	}
+
+	names := strings.Split(query["event"], ",")
+	withIDs := query["id"] != "none"
+
+	h.logger.Info("Streaming server-sent events", map[string]interface{}{
+		"path":  r.proxy.Path,
+		"first": first,
+		"last":  last,
+	})
+
+	return &stream.Response{
+		StatusCode: http.StatusOK,
+		Headers:    responseHeaders("text/event-stream", "Cache-Control", "no-cache", "X-Accel-Buffering", "no"),
+		Body: stream.EventStream(first, last, interval, retry, func(sequence int) (stream.Event, error) {
+			data, err := json.Marshal(models.NewStreamEvent(sequence, r.echo))
+			if err != nil {
+				return stream.Event{}, err
+			}
+			event := stream.Event{
+				Name: strings.TrimSpace(names[sequence%len(names)]),
+				Data: string(data),
+			}
+			if withIDs {
+				event.ID = strconv.Itoa(sequence)
+			}
+			return event, nil
+		}),
+	}
+}
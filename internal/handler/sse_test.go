@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"echo-api/internal/models"
+)
+
+func TestStreamingHandler_SSE(t *testing.T) {
+	handler := NewStreamingHandler()
+
+	response, err := handler.HandleRequest(context.Background(), urlRequest("GET", "/sse/3", map[string]string{
+		"interval": "0",
+		"event":    "ping,pong",
+		"retry":    "2000",
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusOK || response.Headers["Content-Type"] != "text/event-stream" {
+		t.Fatalf("Expected event stream, got %d %v", response.StatusCode, response.Headers)
+	}
+
+	blocks := strings.Split(strings.TrimSuffix(readStream(t, response), "\n\n"), "\n\n")
+	if len(blocks) != 4 || blocks[0] != "retry: 2000" {
+		t.Fatalf("Expected a retry hint and 3 events, got %q", blocks)
+	}
+	for i, block := range blocks[1:] {
+		lines := strings.Split(block, "\n")
+		expectedName := []string{"ping", "pong"}[i%2]
+		if len(lines) != 3 || lines[1] != "event: "+expectedName {
+			t.Fatalf("Unexpected event block %q", block)
+		}
+		var event models.StreamEvent
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &event); err != nil {
+			t.Fatalf("Expected JSON data: %v", err)
+		}
+		if event.Sequence != i || event.Request.Path != "/sse/3" {
+			t.Errorf("Unexpected event %+v", event)
+		}
+	}
+}
+
+func TestStreamingHandler_SSEValidation(t *testing.T) {
+	handler := NewStreamingHandler()
+
+	testCases := []struct {
+		path  string
+		query map[string]string
+	}{
+		{"/sse", map[string]string{"interval": "0"}},
+		{"/sse", map[string]string{"interval": "0.000001"}},
+		{"/sse/5000", nil},
+		{"/sse/100", map[string]string{"interval": "1"}},
+		{"/sse/3", map[string]string{"retry": "soon"}},
+		// An id near the integer limit would overflow the next sequence number
+		{"/sse", map[string]string{"event": "a,b", "interval": "0.1", "lastEventId": "9223372036854775807"}},
+		{"/sse/3", map[string]string{"lastEventId": "9223372036854775807"}},
+	}
+
+	for _, tc := range testCases {
+		response, _ := handler.HandleRequest(context.Background(), urlRequest("GET", tc.path, tc.query))
+		readStream(t, response)
+		if response.StatusCode != http.StatusBadRequest {
+			t.Errorf("For %s %v, expected 400, got %d", tc.path, tc.query, response.StatusCode)
+		}
+	}
+}
+
+func TestStreamingHandler_SSELastEventIDOutOfRange(t *testing.T) {
+	handler := NewStreamingHandler()
+
+	request := urlRequest("GET", "/sse", map[string]string{"event": "a,b", "interval": "0.1"})
+	request.Headers["last-event-id"] = "9223372036854775807"
+	response, err := handler.HandleRequest(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	readStream(t, response)
+	if response.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an out-of-range Last-Event-ID, got %d", response.StatusCode)
+	}
+}
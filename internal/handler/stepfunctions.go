@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/internal/stream"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/lambda/messages"
+)
+
+// taskDirectiveKey is the input field holding the directives for the task echo
+const taskDirectiveKey = "echo"
+
+// taskDirective controls how the task behaves
+type taskDirective struct {
+	// Sleep delays the task by this many seconds, e.g. to trigger a state's TimeoutSeconds
+	Sleep float64 `json:"sleep"`
+	// Error fails the task with this error name, which Retry and Catch match on
+	Error string `json:"error"`
+	Cause string `json:"cause"`
+	// FailUntilRetry only fails attempts whose $$.State.RetryCount is below it, so a retrier can succeed
+	FailUntilRetry int `json:"failUntilRetry"`
+	// Output replaces the echo as the task result
+	Output json.RawMessage `json:"output"`
+}
+
+// StepFunctionsHandler echoes direct Step Functions task invocations
+type StepFunctionsHandler struct {
+	logger *logger.Logger
+}
+
+// NewStepFunctionsHandler creates a new Step Functions task handler instance
+func NewStepFunctionsHandler() *StepFunctionsHandler {
+	return &StepFunctionsHandler{
+		logger: logger.New(),
+	}
+}
+
+// HandleRequest echoes the task input. Passing {"input.$": "$", "context.$": "$$"} as the task parameters
+// adds the execution context; otherwise the whole payload is the input
+func (h *StepFunctionsHandler) HandleRequest(ctx context.Context, payload map[string]interface{}) (interface{}, error) {
+	input, executionContext := splitTaskPayload(payload)
+	echo := models.NewTaskEcho(input, executionContext)
+	echo.RetryCount = taskRetryCount(executionContext)
+
+	directive, err := parseTaskDirective(input)
+	if err != nil {
+		return nil, err
+	}
+
+	h.logger.Info("Processing Step Functions task", map[string]interface{}{
+		"echo":      echo,
+		"directive": directive,
+	})
+
+	if directive == nil {
+		return echo, nil
+	}
+
+	if directive.Sleep > 0 {
+		if err := stream.Sleep(ctx, time.Duration(directive.Sleep*float64(time.Second))); err != nil {
+			return nil, err
+		}
+	}
+
+	if directive.Error != "" && (directive.FailUntilRetry == 0 || echo.RetryCount == nil || *echo.RetryCount < directive.FailUntilRetry) {
+		cause := directive.Cause
+		if cause == "" {
+			cause = fmt.Sprintf("%s requested by the task input", directive.Error)
+		}
+		// The Lambda error type becomes the Step Functions error name
+		return nil, messages.InvokeResponse_Error{
+			Message: cause,
+			Type:    directive.Error,
+		}
+	}
+
+	if directive.Output != nil {
+		return directive.Output, nil
+	}
+	return echo, nil
+}
+
+// splitTaskPayload separates the state input from the context object when both were passed
+func splitTaskPayload(payload map[string]interface{}) (interface{}, interface{}) {
+	input, hasInput := payload["input"]
+	executionContext, hasContext := payload["context"]
+	if hasInput && hasContext && len(payload) == 2 {
+		return input, executionContext
+	}
+	return payload, nil
+}
+
+// taskRetryCount reads $$.State.RetryCount from the context object
+func taskRetryCount(executionContext interface{}) *int {
+	contextObject, _ := executionContext.(map[string]interface{})
+	state, _ := contextObject["State"].(map[string]interface{})
+	count, ok := state["RetryCount"].(float64)
+	if !ok {
+		return nil
+	}
+	retryCount := int(count)
+	return &retryCount
+}
+
+// parseTaskDirective reads the directive object from the input, returning nil when there is none
+func parseTaskDirective(input interface{}) (*taskDirective, error) {
+	fields, _ := input.(map[string]interface{})
+	value, ok := fields[taskDirectiveKey]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	directive := &taskDirective{}
+	if err := json.Unmarshal(data, directive); err != nil {
+		return nil, fmt.Errorf("invalid %s directive: %w", taskDirectiveKey, err)
+	}
+	return directive, nil
+}
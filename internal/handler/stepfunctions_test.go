@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"echo-api/internal/models"
+
+	"github.com/aws/aws-lambda-go/lambda/messages"
+)
+
+// taskPayload decodes a task payload
+func taskPayload(t *testing.T, payload string) map[string]interface{} {
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(payload), &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal payload: %v", err)
+	}
+	return decoded
+}
+
+func TestStepFunctionsHandler_Echo(t *testing.T) {
+	h := NewStepFunctionsHandler()
+
+	result, err := h.HandleRequest(context.Background(), taskPayload(t, `{
+		"input": {"orderId": "o1"},
+		"context": {"Execution": {"Id": "arn:exec"}, "State": {"Name": "Echo", "RetryCount": 2}}
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	echo := result.(*models.TaskEcho)
+	if echo.Input.(map[string]interface{})["orderId"] != "o1" || echo.Context == nil {
+		t.Errorf("Expected input and context to be separated, got %+v", echo)
+	}
+	if echo.RetryCount == nil || *echo.RetryCount != 2 {
+		t.Errorf("Expected retry count 2, got %v", echo.RetryCount)
+	}
+
+	// Without the context object the payload is the input
+	result, _ = h.HandleRequest(context.Background(), taskPayload(t, `{"input": 1}`))
+	if echo := result.(*models.TaskEcho); echo.Context != nil || echo.Input.(map[string]interface{})["input"] != float64(1) {
+		t.Errorf("Expected the whole payload as input, got %+v", echo)
+	}
+}
+
+func TestStepFunctionsHandler_Error(t *testing.T) {
+	h := NewStepFunctionsHandler()
+
+	_, err := h.HandleRequest(context.Background(), taskPayload(t, `{"echo": {"error": "PaymentDeclined", "cause": "card expired"}}`))
+	invokeError, ok := err.(messages.InvokeResponse_Error)
+	if !ok || invokeError.Type != "PaymentDeclined" || invokeError.Message != "card expired" {
+		t.Errorf("Expected a PaymentDeclined error, got %v", err)
+	}
+
+	// failUntilRetry lets the retrier succeed on the given attempt
+	payload := `{"input": {"echo": {"error": "Flaky", "failUntilRetry": 2}}, "context": {"State": {"RetryCount": %d}}}`
+	for retryCount, shouldFail := range map[int]bool{0: true, 1: true, 2: false} {
+		_, err := h.HandleRequest(context.Background(), taskPayload(t, fmt.Sprintf(payload, retryCount)))
+		if (err != nil) != shouldFail {
+			t.Errorf("Retry %d: expected failure %v, got %v", retryCount, shouldFail, err)
+		}
+	}
+}
+
+func TestStepFunctionsHandler_OutputAndSleep(t *testing.T) {
+	h := NewStepFunctionsHandler()
+
+	start := time.Now()
+	result, err := h.HandleRequest(context.Background(), taskPayload(t, `{"echo": {"sleep": 0.05, "output": {"status": "done"}}}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected the task to sleep, took %v", elapsed)
+	}
+	if output := string(result.(json.RawMessage)); output != `{"status":"done"}` {
+		t.Errorf("Expected the shaped output, got %s", output)
+	}
+
+	// The sleep gives up when the invocation times out
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := h.HandleRequest(ctx, taskPayload(t, `{"echo": {"sleep": 5}}`)); err == nil {
+		t.Error("Expected the sleep to be cancelled")
+	}
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/internal/stream"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const (
+	// maxStreamLines caps the number of records /stream/{n} writes
+	maxStreamLines = 1000
+	// maxDripBytes caps the number of bytes /drip writes
+	maxDripBytes = 1024 * 1024
+	// maxStreamDuration keeps a stream within the function timeout
+	maxStreamDuration = 25 * time.Second
+)
+
+// streamRouteHandler serves a route whose body is written incrementally
+type streamRouteHandler func(h *LambdaHandler, ctx context.Context, r *routeRequest) *stream.Response
+
+// streamRoute binds a path pattern to a streaming handler
+type streamRoute struct {
+	pattern string
+	handle  streamRouteHandler
+}
+
+// streamRoutes lists the endpoints that need a streaming transport
+var streamRoutes = []streamRoute{
+	{"/stream/{n}", (*LambdaHandler).handleStream},
+	{"/drip", (*LambdaHandler).handleDrip},
+	{"/sse", (*LambdaHandler).handleSSE},
+	{"/sse/{n}", (*LambdaHandler).handleSSE},
+	{"/bins/{token}/stream", (*LambdaHandler).handleBinStream},
+}
+
+// matchStreamRoute finds the streaming handler for path
+func matchStreamRoute(path string) (streamRouteHandler, map[string]string, bool) {
+	for _, r := range streamRoutes {
+		if params, ok := matchPattern(r.pattern, path); ok {
+			return r.handle, params, true
+		}
+	}
+	return nil, nil, false
+}
+
+// StreamingHandler handles Lambda Function URL requests in RESPONSE_STREAM invoke mode
+type StreamingHandler struct {
+	proxy *LambdaHandler
+}
+
+// NewStreamingHandler creates a new streaming handler instance
+func NewStreamingHandler() *StreamingHandler {
+	return &StreamingHandler{
+		proxy: NewLambdaHandler(),
+	}
+}
+
+// HandleRequest streams the streaming routes and relays every other path through the buffered handler
+func (s *StreamingHandler) HandleRequest(ctx context.Context, request events.LambdaFunctionURLRequest) (*events.LambdaFunctionURLStreamingResponse, error) {
+	proxyRequest := proxyRequestFromURL(request)
+
+	response, ok := s.proxy.Stream(ctx, &proxyRequest)
+	if !ok {
+		buffered, err := s.proxy.serve(ctx, proxyRequest, functionURLInfo(request))
+		if err != nil {
+			return nil, err
+		}
+		response = bufferedStream(buffered)
+	}
+	return streamingResponse(ctx, response), nil
+}
+
+// Stream serves request when its path is a streaming route; ok is false for every other path,
+// which the caller serves with HandleRequest
+func (h *LambdaHandler) Stream(ctx context.Context, request *events.APIGatewayProxyRequest) (*stream.Response, bool) {
+	handle, params, ok := matchStreamRoute(request.Path)
+	if !ok || !h.isMethodAllowed(request.HTTPMethod) {
+		return nil, false
+	}
+
+	h.logger.Info("Streaming request", map[string]interface{}{
+		"method": request.HTTPMethod,
+		"path":   request.Path,
+	})
+
+	echoRequest := h.parseRequest(request)
+	return handle(h, ctx, &routeRequest{proxy: request, echo: echoRequest, params: params}), true
+}
+
+// handleStream writes n NDJSON records, each echoing the request with a sequence number
+func (h *LambdaHandler) handleStream(ctx context.Context, r *routeRequest) *stream.Response {
+	n, err := strconv.Atoi(r.params["n"])
+	if err != nil || n < 0 || n > maxStreamLines {
+		return h.errorStream(http.StatusBadRequest, "Bad Request", fmt.Sprintf("n must be an integer between 0 and %d", maxStreamLines))
+	}
+	interval, err := streamSeconds(r.echo.QueryParams["interval"], 0)
+	if err != nil || interval*time.Duration(n) > maxStreamDuration {
+		return h.errorStream(http.StatusBadRequest, "Bad Request", fmt.Sprintf("interval must be seconds and the stream must finish within %v", maxStreamDuration))
+	}
+
+	return &stream.Response{
+		StatusCode: http.StatusOK,
+		Headers:    responseHeaders("application/x-ndjson", "Cache-Control", "no-store"),
+		Body: stream.NDJSON(n, interval, func(sequence int) interface{} {
+			return models.NewStreamEvent(sequence, r.echo)
+		}),
+	}
+}
+
+// handleDrip writes ?numbytes= bytes over ?duration= seconds after ?delay= seconds, answering with ?code=
+func (h *LambdaHandler) handleDrip(ctx context.Context, r *routeRequest) *stream.Response {
+	query := r.echo.QueryParams
+
+	numBytes := 10
+	if value := query["numbytes"]; value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 || parsed > maxDripBytes {
+			return h.errorStream(http.StatusBadRequest, "Bad Request", fmt.Sprintf("numbytes must be an integer between 0 and %d", maxDripBytes))
+		}
+		numBytes = parsed
+	}
+
+	code := http.StatusOK
+	if value := query["code"]; value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 200 || parsed > 599 {
+			return h.errorStream(http.StatusBadRequest, "Bad Request", "code must be an HTTP status between 200 and 599")
+		}
+		code = parsed
+	}
+
+	duration, err := streamSeconds(query["duration"], 2*time.Second)
+	if err != nil {
+		return h.errorStream(http.StatusBadRequest, "Bad Request", "duration must be a number of seconds")
+	}
+	delay, err := streamSeconds(query["delay"], 0)
+	if err != nil {
+		return h.errorStream(http.StatusBadRequest, "Bad Request", "delay must be a number of seconds")
+	}
+	if duration+delay > maxStreamDuration {
+		return h.errorStream(http.StatusBadRequest, "Bad Request", fmt.Sprintf("duration and delay must add up to at most %v", maxStreamDuration))
+	}
+
+	return &stream.Response{
+		StatusCode: code,
+		Headers:    responseHeaders("application/octet-stream", "Cache-Control", "no-store"),
+		Body:       stream.Drip(numBytes, duration, delay),
+	}
+}
+
+// errorStream wraps a standard error response as a stream
+func (h *LambdaHandler) errorStream(statusCode int, error, message string) *stream.Response {
+	response, _ := h.createErrorResponse(statusCode, error, message)
+	return bufferedStream(response)
+}
+
+// streamSeconds parses a non-negative number of seconds such as "1.5", returning fallback when empty
+func streamSeconds(value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("invalid seconds %q", value)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// bufferedStream converts a buffered proxy response into a stream, undoing API Gateway's base64 encoding
+func bufferedStream(response events.APIGatewayProxyResponse) *stream.Response {
+	body := []byte(response.Body)
+	if response.IsBase64Encoded {
+		if decoded, err := base64.StdEncoding.DecodeString(response.Body); err == nil {
+			body = decoded
+		}
+	}
+	return stream.Buffered(response.StatusCode, response.Headers, body)
+}
+
+// streamingResponse connects a stream to the Function URL streaming response
+func streamingResponse(ctx context.Context, response *stream.Response) *events.LambdaFunctionURLStreamingResponse {
+	return &events.LambdaFunctionURLStreamingResponse{
+		StatusCode: response.StatusCode,
+		Headers:    response.Headers,
+		Body:       response.Reader(ctx),
+	}
+}
+
+// proxyRequestFromURL converts a Function URL (payload 2.0) request to the proxy request the handlers work with
+func proxyRequestFromURL(request events.LambdaFunctionURLRequest) events.APIGatewayProxyRequest {
+	headers := make(map[string]string, len(request.Headers)+1)
+	for key, value := range request.Headers {
+		headers[key] = value
+	}
+	// Payload 2.0 moves cookies out of the headers
+	if len(request.Cookies) > 0 {
+		headers["cookie"] = strings.Join(request.Cookies, "; ")
+	}
+
+	path := request.RawPath
+	if path == "" {
+		path = "/"
+	}
+
+	return events.APIGatewayProxyRequest{
+		HTTPMethod:            request.RequestContext.HTTP.Method,
+		Path:                  path,
+		Headers:               headers,
+		QueryStringParameters: request.QueryStringParameters,
+		Body:                  request.Body,
+		IsBase64Encoded:       request.IsBase64Encoded,
+		RequestContext: events.APIGatewayProxyRequestContext{
+			AccountID:  request.RequestContext.AccountID,
+			RequestID:  request.RequestContext.RequestID,
+			APIID:      request.RequestContext.APIID,
+			DomainName: request.RequestContext.DomainName,
+			HTTPMethod: request.RequestContext.HTTP.Method,
+			Path:       path,
+			Identity: events.APIGatewayRequestIdentity{
+				SourceIP:  request.RequestContext.HTTP.SourceIP,
+				UserAgent: request.RequestContext.HTTP.UserAgent,
+			},
+		},
+	}
+}
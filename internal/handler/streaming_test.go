@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"echo-api/internal/models"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func urlRequest(method, path string, query map[string]string) events.LambdaFunctionURLRequest {
+	return events.LambdaFunctionURLRequest{
+		RawPath:               path,
+		Headers:               map[string]string{"user-agent": "test-agent"},
+		QueryStringParameters: query,
+		Cookies:               []string{"a=1", "b=2"},
+		RequestContext: events.LambdaFunctionURLRequestContext{
+			RequestID: "req-1",
+			HTTP:      events.LambdaFunctionURLRequestContextHTTPDescription{Method: method, Path: path},
+		},
+	}
+}
+
+func readStream(t *testing.T, response *events.LambdaFunctionURLStreamingResponse) string {
+	t.Helper()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("Failed to read stream: %v", err)
+	}
+	return string(body)
+}
+
+func TestStreamingHandler_Stream(t *testing.T) {
+	handler := NewStreamingHandler()
+
+	response, err := handler.HandleRequest(context.Background(), urlRequest("GET", "/stream/3", nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusOK || response.Headers["Content-Type"] != "application/x-ndjson" {
+		t.Errorf("Expected NDJSON stream, got %d %v", response.StatusCode, response.Headers)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(readStream(t, response), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d", len(lines))
+	}
+	for i, line := range lines {
+		var event models.StreamEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Line %d is not JSON: %v", i, err)
+		}
+		if event.Sequence != i || event.Request.Path != "/stream/3" {
+			t.Errorf("Unexpected event %+v", event)
+		}
+		if event.Request.Header("cookie") != "a=1; b=2" {
+			t.Errorf("Expected cookies to be folded into the cookie header, got %q", event.Request.Header("cookie"))
+		}
+	}
+}
+
+func TestStreamingHandler_Drip(t *testing.T) {
+	handler := NewStreamingHandler()
+
+	response, _ := handler.HandleRequest(context.Background(), urlRequest("GET", "/drip", map[string]string{
+		"numbytes": "4",
+		"duration": "0",
+		"code":     "201",
+	}))
+	if response.StatusCode != http.StatusCreated {
+		t.Errorf("Expected status 201, got %d", response.StatusCode)
+	}
+	if body := readStream(t, response); body != "****" {
+		t.Errorf("Expected 4 bytes, got %q", body)
+	}
+
+	invalid, _ := handler.HandleRequest(context.Background(), urlRequest("GET", "/drip", map[string]string{"duration": "60"}))
+	if invalid.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a drip longer than the function timeout, got %d", invalid.StatusCode)
+	}
+	readStream(t, invalid)
+}
+
+func TestStreamingHandler_RelaysBufferedRoutes(t *testing.T) {
+	handler := NewStreamingHandler()
+
+	response, _ := handler.HandleRequest(context.Background(), urlRequest("GET", "/range/4", nil))
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", response.StatusCode)
+	}
+	if body := readStream(t, response); body != "abcd" {
+		t.Errorf("Expected decoded range bytes, got %q", body)
+	}
+
+	echo, _ := handler.HandleRequest(context.Background(), urlRequest("GET", "/hello", nil))
+	var echoResponse models.EchoResponse
+	if err := json.Unmarshal([]byte(readStream(t, echo)), &echoResponse); err != nil {
+		t.Fatalf("Expected JSON echo: %v", err)
+	}
+	if echoResponse.Request.Path != "/hello" || echoResponse.Request.Method != "GET" {
+		t.Errorf("Unexpected echo %+v", echoResponse.Request)
+	}
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"echo-api/internal/kpl"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func kinesisRecord(shard, sequence string, data []byte) events.KinesisEventRecord {
+	return events.KinesisEventRecord{
+		EventID:        shard + ":" + sequence,
+		EventName:      "aws:kinesis:record",
+		EventSource:    "aws:kinesis",
+		EventSourceArn: "arn:aws:kinesis:us-east-1:123456789012:stream/echo",
+		Kinesis: events.KinesisRecord{
+			PartitionKey:   "pk",
+			SequenceNumber: sequence,
+			Data:           data,
+		},
+	}
+}
+
+func TestKinesisHandler(t *testing.T) {
+	handler := NewKinesisHandler()
+	aggregated := kpl.Aggregate([]kpl.Record{
+		{PartitionKey: "a", Data: []byte(`{"n":1}`)},
+		{PartitionKey: "b", Data: []byte(`{"n":2,"echo-fail":true}`)},
+	})
+
+	response, err := handler.HandleRequest(context.Background(), events.KinesisEvent{Records: []events.KinesisEventRecord{
+		kinesisRecord("shardId-000000000000", "100", []byte(`{"hello":"world"}`)),
+		kinesisRecord("shardId-000000000000", "101", aggregated),
+		kinesisRecord("shardId-000000000001", "200", []byte{0xff, 0x00}),
+	}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.BatchItemFailures) != 1 || response.BatchItemFailures[0].ItemIdentifier != "101" {
+		t.Errorf("Expected the aggregated record to fail, got %+v", response.BatchItemFailures)
+	}
+}
+
+func TestKinesisEchoes(t *testing.T) {
+	aggregated := kpl.Aggregate([]kpl.Record{
+		{PartitionKey: "a", Data: []byte(`{"n":1}`)},
+		{PartitionKey: "b", Data: []byte("text")},
+	})
+	echoes := kinesisEchoes(kinesisRecord("shardId-000000000000", "101", aggregated))
+	if len(echoes) != 2 {
+		t.Fatalf("Expected 2 user records, got %d", len(echoes))
+	}
+	if echoes[0].Attributes["partitionKey"] != "a" || echoes[0].Detail == nil || echoes[1].Body != "text" {
+		t.Errorf("Unexpected user records %+v %+v", echoes[0], echoes[1])
+	}
+	if echoes[1].Attributes["subSequenceNumber"] != 1 || echoes[1].Attributes["shardId"] != "shardId-000000000000" {
+		t.Errorf("Unexpected attributes %v", echoes[1].Attributes)
+	}
+
+	binary := kinesisEchoes(kinesisRecord("shardId-000000000001", "200", []byte{0xff, 0x00}))[0]
+	if binary.Body != "/wA=" || binary.Attributes["isBase64Encoded"] != true {
+		t.Errorf("Expected base64 body for binary data, got %+v", binary)
+	}
+}
+
+func TestDynamoDBHandler(t *testing.T) {
+	var event events.DynamoDBEvent
+	err := json.Unmarshal([]byte(`{"Records": [
+		{
+			"eventID": "e1", "eventName": "MODIFY", "eventSource": "aws:dynamodb",
+			"eventSourceARN": "arn:aws:dynamodb:us-east-1:123456789012:table/echo/stream/2024",
+			"dynamodb": {
+				"Keys": {"id": {"S": "o1"}},
+				"OldImage": {"id": {"S": "o1"}, "status": {"S": "new"}, "note": {"S": "x"}},
+				"NewImage": {"id": {"S": "o1"}, "status": {"S": "paid"}, "total": {"N": "5"}},
+				"SequenceNumber": "300", "SizeBytes": 50, "StreamViewType": "NEW_AND_OLD_IMAGES"
+			}
+		},
+		{
+			"eventID": "e2", "eventName": "INSERT", "eventSource": "aws:dynamodb",
+			"eventSourceARN": "arn:aws:dynamodb:us-east-1:123456789012:table/echo/stream/2024",
+			"dynamodb": {
+				"Keys": {"id": {"S": "o2"}},
+				"NewImage": {"id": {"S": "o2"}, "echo-fail": {"BOOL": true}},
+				"SequenceNumber": "301", "SizeBytes": 20, "StreamViewType": "NEW_AND_OLD_IMAGES"
+			}
+		}
+	]}`), &event)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal event: %v", err)
+	}
+
+	response, _ := NewDynamoDBHandler().HandleRequest(context.Background(), event)
+	if len(response.BatchItemFailures) != 1 || response.BatchItemFailures[0].ItemIdentifier != "301" {
+		t.Errorf("Expected record 301 to fail, got %+v", response.BatchItemFailures)
+	}
+
+	echo := dynamoDBEcho(event.Records[0])
+	data, _ := json.Marshal(echo.Detail.(map[string]interface{})["diff"])
+	expected := `{"added":{"total":5},"removed":{"note":"x"},"changed":{"status":{"old":"new","new":"paid"}}}`
+	if string(data) != expected {
+		t.Errorf("Expected diff %s, got %s", expected, data)
+	}
+	if _, ok := dynamoDBEcho(event.Records[1]).Detail.(map[string]interface{})["diff"]; ok {
+		t.Error("Expected no diff for an insert")
+	}
+}
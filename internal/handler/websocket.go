@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"echo-api/internal/apigw"
+	"echo-api/internal/codec"
+	"echo-api/internal/models"
+	"echo-api/internal/sigv4"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// WebSocketHandler handles API Gateway WebSocket API events, echoing each message to its sender
+type WebSocketHandler struct {
+	logger   *logger.Logger
+	poster   apigw.Poster
+	endpoint string
+}
+
+// NewWebSocketHandler creates a WebSocket handler that posts through the management API with the function's credentials
+func NewWebSocketHandler() *WebSocketHandler {
+	signer := sigv4.NewSigner(sigv4.AWSCredentialsFromEnv(), os.Getenv("AWS_REGION"), "execute-api")
+	return NewWebSocketHandlerWithPoster(apigw.NewClient(signer))
+}
+
+// NewWebSocketHandlerWithPoster creates a WebSocket handler that delivers messages through poster;
+// WEBSOCKET_ENDPOINT overrides the management endpoint derived from the request
+func NewWebSocketHandlerWithPoster(poster apigw.Poster) *WebSocketHandler {
+	return &WebSocketHandler{
+		logger:   logger.New(),
+		poster:   poster,
+		endpoint: os.Getenv("WEBSOCKET_ENDPOINT"),
+	}
+}
+
+// HandleRequest accepts $connect and $disconnect and echoes every other route back to the connection
+func (h *WebSocketHandler) HandleRequest(ctx context.Context, request events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+	requestContext := request.RequestContext
+	h.logger.Info("Processing WebSocket event", map[string]interface{}{
+		"route_key":     requestContext.RouteKey,
+		"event_type":    requestContext.EventType,
+		"connection_id": requestContext.ConnectionID,
+	})
+
+	switch requestContext.RouteKey {
+	case "$connect", "$disconnect":
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}
+
+	message := &models.WebSocketMessage{
+		ConnectionID:    requestContext.ConnectionID,
+		RouteKey:        requestContext.RouteKey,
+		Body:            request.Body,
+		IsBase64Encoded: request.IsBase64Encoded,
+		RequestContext:  requestContext,
+		Timestamp:       time.Now().UTC().Format(time.RFC3339),
+	}
+	if !request.IsBase64Encoded && json.Valid([]byte(request.Body)) {
+		message.ParsedBody, _ = codec.DecodeBody("application/json", []byte(request.Body))
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		h.logger.Error("Failed to marshal WebSocket echo", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusInternalServerError}, nil
+	}
+
+	endpoint := h.endpoint
+	if endpoint == "" {
+		endpoint = apigw.Endpoint(requestContext.DomainName, requestContext.Stage)
+	}
+	if err := h.poster.PostToConnection(ctx, endpoint, requestContext.ConnectionID, data); err != nil {
+		if errors.Is(err, apigw.ErrGone) {
+			h.logger.Warn("Connection closed before the echo was delivered", map[string]interface{}{
+				"connection_id": requestContext.ConnectionID,
+			})
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusGone}, nil
+		}
+		h.logger.Error("Failed to post to connection", map[string]interface{}{
+			"connection_id": requestContext.ConnectionID,
+			"endpoint":      endpoint,
+			"error":         err.Error(),
+		})
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusBadGateway}, nil
+	}
+
+	return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+}
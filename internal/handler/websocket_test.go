@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"echo-api/internal/apigw"
+	"echo-api/internal/models"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func websocketRequest(routeKey, body string) events.APIGatewayWebsocketProxyRequest {
+	return events.APIGatewayWebsocketProxyRequest{
+		Body: body,
+		RequestContext: events.APIGatewayWebsocketProxyRequestContext{
+			RouteKey:     routeKey,
+			ConnectionID: "L0SM9cOFvHcCIhw=",
+			DomainName:   "abc.execute-api.us-east-1.amazonaws.com",
+			Stage:        "prod",
+			RequestID:    "req-1",
+		},
+	}
+}
+
+func TestWebSocketHandler_Echo(t *testing.T) {
+	poster := apigw.NewMemoryPoster()
+	handler := NewWebSocketHandlerWithPoster(poster)
+	ctx := context.Background()
+
+	for _, routeKey := range []string{"$connect", "$disconnect"} {
+		response, err := handler.HandleRequest(ctx, websocketRequest(routeKey, ""))
+		if err != nil || response.StatusCode != http.StatusOK {
+			t.Errorf("Expected %s to be accepted, got %d %v", routeKey, response.StatusCode, err)
+		}
+	}
+	if len(poster.Messages("L0SM9cOFvHcCIhw=")) != 0 {
+		t.Error("Expected no echo for $connect or $disconnect")
+	}
+
+	response, _ := handler.HandleRequest(ctx, websocketRequest("$default", `{"action":"say","text":"hi"}`))
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", response.StatusCode)
+	}
+
+	messages := poster.Messages("L0SM9cOFvHcCIhw=")
+	if len(messages) != 1 {
+		t.Fatalf("Expected one echo, got %d", len(messages))
+	}
+	var message models.WebSocketMessage
+	if err := json.Unmarshal(messages[0], &message); err != nil {
+		t.Fatalf("Expected JSON echo: %v", err)
+	}
+	if message.RouteKey != "$default" || message.Body != `{"action":"say","text":"hi"}` {
+		t.Errorf("Unexpected echo %+v", message)
+	}
+	if parsed, ok := message.ParsedBody.(map[string]interface{}); !ok || parsed["text"] != "hi" {
+		t.Errorf("Expected parsed JSON body, got %v", message.ParsedBody)
+	}
+	if requestContext, ok := message.RequestContext.(map[string]interface{}); !ok || requestContext["requestId"] != "req-1" {
+		t.Errorf("Expected the request context in the echo, got %v", message.RequestContext)
+	}
+}
+
+func TestWebSocketHandler_Gone(t *testing.T) {
+	poster := apigw.NewMemoryPoster()
+	poster.Disconnect("L0SM9cOFvHcCIhw=")
+
+	response, _ := NewWebSocketHandlerWithPoster(poster).HandleRequest(context.Background(), websocketRequest("$default", "hi"))
+	if response.StatusCode != http.StatusGone {
+		t.Errorf("Expected status 410, got %d", response.StatusCode)
+	}
+}
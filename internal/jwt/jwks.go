@@ -0,0 +1,265 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+const (
+	// EnvJWKSFile names the environment variable holding a path to a JWKS file
+	EnvJWKSFile = "JWT_JWKS_FILE"
+	// EnvJWKS names the environment variable holding an inline JWKS document
+	EnvJWKS = "JWT_JWKS"
+)
+
+// KeySet holds the keys used to verify token signatures
+type KeySet struct {
+	Keys []*Key
+}
+
+// Key represents a single verification key from a JWKS document
+type Key struct {
+	ID        string
+	Algorithm string
+	public    crypto.PublicKey
+	secret    []byte
+}
+
+// jsonWebKey is the wire format of a JWK entry
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// KeySetFromEnv loads the key set named by JWT_JWKS_FILE or JWT_JWKS; it returns nil when neither is set
+func KeySetFromEnv() (*KeySet, error) {
+	if path := os.Getenv(EnvJWKSFile); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", EnvJWKSFile, err)
+		}
+		return ParseKeySet(data)
+	}
+	if inline := os.Getenv(EnvJWKS); inline != "" {
+		return ParseKeySet([]byte(inline))
+	}
+	return nil, nil
+}
+
+// ParseKeySet parses a JWKS document ({"keys": [...]})
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid JWKS document: %w", err)
+	}
+
+	keySet := &KeySet{}
+	for index, jwk := range document.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := parseKey(jwk)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key at index %d: %w", index, err)
+		}
+		keySet.Keys = append(keySet.Keys, key)
+	}
+	return keySet, nil
+}
+
+// candidates returns the keys matching kid, or every key when the token has no kid
+func (s *KeySet) candidates(kid string) []*Key {
+	if kid == "" {
+		return s.Keys
+	}
+	var keys []*Key
+	for _, key := range s.Keys {
+		if key.ID == kid {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// parseKey converts a JWK entry into a verification key
+func parseKey(jwk jsonWebKey) (*Key, error) {
+	key := &Key{ID: jwk.Kid, Algorithm: jwk.Alg}
+
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil || !e.IsInt64() {
+			return nil, errors.New("invalid exponent")
+		}
+		key.public = &rsa.PublicKey{N: n, E: int(e.Int64())}
+	case "EC":
+		curve, err := namedCurve(jwk.Crv)
+		if err != nil {
+			return nil, err
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+		key.public = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported OKP curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 public key")
+		}
+		key.public = ed25519.PublicKey(x)
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(jwk.K)
+		if err != nil || len(secret) == 0 {
+			return nil, errors.New("invalid symmetric key")
+		}
+		key.secret = secret
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+
+	return key, nil
+}
+
+// verify checks a signature made with alg over signingInput
+func (k *Key) verify(alg string, signingInput, signature []byte) error {
+	if k.Algorithm != "" && k.Algorithm != alg {
+		return fmt.Errorf("key %q is restricted to %s", k.ID, k.Algorithm)
+	}
+
+	if alg == "EdDSA" {
+		public, ok := k.public.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("key %q is not an Ed25519 key", k.ID)
+		}
+		if !ed25519.Verify(public, signingInput, signature) {
+			return errors.New("signature does not match")
+		}
+		return nil
+	}
+
+	hash, err := hashFor(alg)
+	if err != nil {
+		return err
+	}
+
+	switch alg[:2] {
+	case "HS":
+		if k.secret == nil {
+			return fmt.Errorf("key %q is not a symmetric key", k.ID)
+		}
+		mac := hmac.New(hash.New, k.secret)
+		mac.Write(signingInput)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return errors.New("signature does not match")
+		}
+		return nil
+	case "RS", "PS":
+		public, ok := k.public.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key %q is not an RSA key", k.ID)
+		}
+		digest := hash.New()
+		digest.Write(signingInput)
+		if alg[0] == 'R' {
+			err = rsa.VerifyPKCS1v15(public, hash, digest.Sum(nil), signature)
+		} else {
+			err = rsa.VerifyPSS(public, hash, digest.Sum(nil), signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		if err != nil {
+			return errors.New("signature does not match")
+		}
+		return nil
+	case "ES":
+		public, ok := k.public.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key %q is not an EC key", k.ID)
+		}
+		size := (public.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("signature has the wrong length for the key curve")
+		}
+		digest := hash.New()
+		digest.Write(signingInput)
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(public, digest.Sum(nil), r, s) {
+			return errors.New("signature does not match")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unsupported algorithm %q", alg)
+}
+
+// hashFor returns the digest used by an HS/RS/PS/ES algorithm
+func hashFor(alg string) (crypto.Hash, error) {
+	if len(alg) == 5 && strings.Contains("HS RS PS ES", alg[:2]) {
+		switch alg[2:] {
+		case "256":
+			return crypto.SHA256, nil
+		case "384":
+			return crypto.SHA384, nil
+		case "512":
+			return crypto.SHA512, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported algorithm %q", alg)
+}
+
+// namedCurve maps a JWK crv value to its elliptic curve
+func namedCurve(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	}
+	return nil, fmt.Errorf("unsupported EC curve %q", name)
+}
+
+// decodeBigInt decodes a base64url big-endian integer
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, errors.New("not valid base64url")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,117 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+// encodeInt encodes a big integer as base64url, padded to size bytes when size > 0
+func encodeInt(value *big.Int, size int) string {
+	data := value.Bytes()
+	if size > len(data) {
+		data = append(make([]byte, size-len(data)), data...)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// signingInput builds the header.claims part of a token
+func signingInput(header map[string]interface{}) string {
+	headerJSON, _ := json.Marshal(header)
+	return base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"a"}`))
+}
+
+func TestParseKeySet_Asymmetric(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+
+	document := map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "n": encodeInt(rsaKey.N, 0), "e": encodeInt(big.NewInt(int64(rsaKey.E)), 0)},
+			{"kty": "EC", "kid": "ec", "crv": "P-256", "x": encodeInt(ecKey.X, 32), "y": encodeInt(ecKey.Y, 32)},
+			{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": base64.RawURLEncoding.EncodeToString(edPublic)},
+			{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		},
+	}
+	data, _ := json.Marshal(document)
+	keys, err := ParseKeySet(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(keys.Keys) != 3 {
+		t.Fatalf("Expected 3 signing keys, got %d", len(keys.Keys))
+	}
+	inspector := NewInspector(keys)
+
+	// RS256
+	input := signingInput(map[string]interface{}{"alg": "RS256", "kid": "rsa"})
+	digest := sha256.Sum256([]byte(input))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+	if info := inspector.Inspect(input + "." + base64.RawURLEncoding.EncodeToString(signature)); info.Signature.Status != StatusVerified {
+		t.Errorf("Expected RS256 signature to verify, got %+v", info.Signature)
+	}
+
+	// ES256
+	input = signingInput(map[string]interface{}{"alg": "ES256", "kid": "ec"})
+	digest = sha256.Sum256([]byte(input))
+	r, s, _ := ecdsa.Sign(rand.Reader, ecKey, digest[:])
+	signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	if info := inspector.Inspect(input + "." + base64.RawURLEncoding.EncodeToString(signature)); info.Signature.Status != StatusVerified {
+		t.Errorf("Expected ES256 signature to verify, got %+v", info.Signature)
+	}
+
+	// EdDSA without kid tries every key
+	input = signingInput(map[string]interface{}{"alg": "EdDSA"})
+	signature = ed25519.Sign(edPrivate, []byte(input))
+	info := inspector.Inspect(input + "." + base64.RawURLEncoding.EncodeToString(signature))
+	if info.Signature.Status != StatusVerified || info.Signature.KeyID != "ed" {
+		t.Errorf("Expected EdDSA signature to verify with key ed, got %+v", info.Signature)
+	}
+}
+
+func TestParseKeySet_Invalid(t *testing.T) {
+	testCases := []string{
+		`not json`,
+		`{"keys":[{"kty":"XYZ"}]}`,
+		`{"keys":[{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}]}`,
+		`{"keys":[{"kty":"oct","k":""}]}`,
+	}
+
+	for _, tc := range testCases {
+		if _, err := ParseKeySet([]byte(tc)); err == nil {
+			t.Errorf("Expected error for %s", tc)
+		}
+	}
+}
+
+func TestKeySetFromEnv(t *testing.T) {
+	t.Setenv(EnvJWKSFile, "")
+	t.Setenv(EnvJWKS, "")
+	keys, err := KeySetFromEnv()
+	if err != nil || keys != nil {
+		t.Errorf("Expected no key set when unconfigured, got %v, %v", keys, err)
+	}
+
+	t.Setenv(EnvJWKS, `{"keys":[{"kty":"oct","kid":"k","k":"c2VjcmV0"}]}`)
+	keys, err = KeySetFromEnv()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(keys.Keys) != 1 || keys.Keys[0].ID != "k" {
+		t.Errorf("Expected inline key k, got %+v", keys.Keys)
+	}
+
+	t.Setenv(EnvJWKSFile, "/nonexistent/jwks.json")
+	if _, err := KeySetFromEnv(); err == nil {
+		t.Error("Expected error for missing JWKS file")
+	}
+}
@@ -0,0 +1,161 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"echo-api/internal/models"
+)
+
+const (
+	// StatusVerified means the signature matched a configured key
+	StatusVerified = "verified"
+	// StatusFailed means verification was attempted and did not succeed
+	StatusFailed = "failed"
+	// StatusSkipped means no key set is configured, so the signature was not checked
+	StatusSkipped = "skipped"
+)
+
+// Inspector decodes bearer tokens and verifies their signatures when a key set is configured
+type Inspector struct {
+	keys *KeySet
+	now  func() time.Time
+}
+
+// NewInspector creates a new Inspector; keys may be nil to only decode tokens
+func NewInspector(keys *KeySet) *Inspector {
+	return &Inspector{
+		keys: keys,
+		now:  time.Now,
+	}
+}
+
+// BearerToken extracts the token from an Authorization header value
+func BearerToken(authorization string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// Inspect decodes the token header and claims, reports its time validity and checks its signature
+func (i *Inspector) Inspect(raw string) *models.TokenInfo {
+	info := &models.TokenInfo{
+		Signature: models.TokenSignature{Status: StatusSkipped},
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		info.Error = "token must have three dot-separated segments"
+		return info
+	}
+
+	header, err := decodeSegment(parts[0])
+	if err != nil {
+		info.Error = fmt.Sprintf("invalid header: %v", err)
+		return info
+	}
+	info.Header = header
+	info.Signature.Algorithm, _ = header["alg"].(string)
+	info.Signature.KeyID, _ = header["kid"].(string)
+
+	claims, err := decodeSegment(parts[1])
+	if err != nil {
+		info.Error = fmt.Sprintf("invalid claims: %v", err)
+		return info
+	}
+	info.Claims = claims
+
+	now := i.now()
+	if exp, ok := numericDate(claims["exp"]); ok {
+		info.Expiry = &models.TokenExpiry{
+			At:      exp.UTC().Format(time.RFC3339),
+			Expired: !now.Before(exp),
+		}
+	}
+	if nbf, ok := numericDate(claims["nbf"]); ok {
+		info.NotBefore = &models.TokenNotBefore{
+			At:     nbf.UTC().Format(time.RFC3339),
+			Active: !now.Before(nbf),
+		}
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		info.Signature.Status = StatusFailed
+		info.Signature.Error = "signature is not valid base64url"
+		return info
+	}
+	i.verify(&info.Signature, parts[0]+"."+parts[1], signature)
+
+	return info
+}
+
+// verify checks the signature against the configured key set and records the outcome
+func (i *Inspector) verify(result *models.TokenSignature, signingInput string, signature []byte) {
+	if i.keys == nil || len(i.keys.Keys) == 0 {
+		result.Error = "no key set configured"
+		return
+	}
+
+	result.Status = StatusFailed
+	if result.Algorithm == "" || strings.EqualFold(result.Algorithm, "none") {
+		result.Error = "unsigned tokens are not accepted"
+		return
+	}
+
+	candidates := i.keys.candidates(result.KeyID)
+	if len(candidates) == 0 {
+		result.Error = fmt.Sprintf("no key found for kid %q", result.KeyID)
+		return
+	}
+
+	for _, key := range candidates {
+		err := key.verify(result.Algorithm, []byte(signingInput), signature)
+		if err == nil {
+			result.Status = StatusVerified
+			result.KeyID = key.ID
+			result.Error = ""
+			return
+		}
+		result.Error = err.Error()
+	}
+}
+
+// decodeSegment decodes a base64url JSON segment, keeping numbers exact
+func decodeSegment(segment string) (map[string]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return nil, fmt.Errorf("not valid base64url")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var values map[string]interface{}
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("not a JSON object")
+	}
+	return values, nil
+}
+
+// numericDate converts a NumericDate claim (seconds since the epoch) to a time
+func numericDate(value interface{}) (time.Time, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	whole, fraction := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(fraction*1e9)), true
+}
@@ -0,0 +1,110 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// signHS256 builds a compact HS256 token for tests
+func signHS256(t *testing.T, header, claims map[string]interface{}, secret []byte) string {
+	t.Helper()
+	headerJSON, _ := json.Marshal(header)
+	claimsJSON, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestBearerToken(t *testing.T) {
+	testCases := []struct {
+		header   string
+		expected string
+		ok       bool
+	}{
+		{"Bearer abc.def.ghi", "abc.def.ghi", true},
+		{"bearer   abc.def.ghi ", "abc.def.ghi", true},
+		{"Basic dXNlcjpwYXNz", "", false},
+		{"Bearer ", "", false},
+		{"", "", false},
+	}
+
+	for _, tc := range testCases {
+		token, ok := BearerToken(tc.header)
+		if token != tc.expected || ok != tc.ok {
+			t.Errorf("For %q, expected (%q, %v), got (%q, %v)", tc.header, tc.expected, tc.ok, token, ok)
+		}
+	}
+}
+
+func TestInspect_DecodesWithoutKeySet(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	token := signHS256(t,
+		map[string]interface{}{"alg": "HS256", "typ": "JWT"},
+		map[string]interface{}{"sub": "user-1", "exp": now.Add(-time.Minute).Unix(), "nbf": now.Add(-time.Hour).Unix()},
+		[]byte("secret"),
+	)
+
+	inspector := NewInspector(nil)
+	inspector.now = func() time.Time { return now }
+	info := inspector.Inspect(token)
+
+	if info.Error != "" {
+		t.Fatalf("Unexpected error: %s", info.Error)
+	}
+	if info.Header["alg"] != "HS256" {
+		t.Errorf("Expected alg HS256, got %v", info.Header["alg"])
+	}
+	if info.Claims["sub"] != "user-1" {
+		t.Errorf("Expected sub user-1, got %v", info.Claims["sub"])
+	}
+	if info.Expiry == nil || !info.Expiry.Expired {
+		t.Errorf("Expected token to be reported as expired, got %+v", info.Expiry)
+	}
+	if info.NotBefore == nil || !info.NotBefore.Active {
+		t.Errorf("Expected token to be reported as active, got %+v", info.NotBefore)
+	}
+	if info.Signature.Status != StatusSkipped {
+		t.Errorf("Expected signature status %s, got %s", StatusSkipped, info.Signature.Status)
+	}
+}
+
+func TestInspect_VerifiesWithKeySet(t *testing.T) {
+	secret := []byte("top-secret")
+	keys, err := ParseKeySet([]byte(`{"keys":[{"kty":"oct","kid":"k1","k":"` + base64.RawURLEncoding.EncodeToString(secret) + `"}]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	inspector := NewInspector(keys)
+
+	valid := signHS256(t, map[string]interface{}{"alg": "HS256", "kid": "k1"}, map[string]interface{}{"sub": "a"}, secret)
+	if info := inspector.Inspect(valid); info.Signature.Status != StatusVerified {
+		t.Errorf("Expected signature to verify, got %+v", info.Signature)
+	}
+
+	forged := signHS256(t, map[string]interface{}{"alg": "HS256", "kid": "k1"}, map[string]interface{}{"sub": "a"}, []byte("wrong"))
+	info := inspector.Inspect(forged)
+	if info.Signature.Status != StatusFailed || info.Signature.Error == "" {
+		t.Errorf("Expected signature to fail with a reason, got %+v", info.Signature)
+	}
+
+	unknownKid := signHS256(t, map[string]interface{}{"alg": "HS256", "kid": "other"}, map[string]interface{}{}, secret)
+	if info := inspector.Inspect(unknownKid); info.Signature.Status != StatusFailed {
+		t.Errorf("Expected unknown kid to fail, got %+v", info.Signature)
+	}
+}
+
+func TestInspect_MalformedToken(t *testing.T) {
+	inspector := NewInspector(nil)
+
+	if info := inspector.Inspect("not-a-token"); info.Error == "" {
+		t.Error("Expected error for token without segments")
+	}
+	if info := inspector.Inspect("!!!.e30.sig"); info.Error == "" {
+		t.Error("Expected error for undecodable header")
+	}
+}
@@ -0,0 +1,184 @@
+package kpl
+
+import (
+	"bytes"
+	"crypto/md5"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Magic prefixes every record aggregated by the Kinesis Producer Library
+var Magic = []byte{0xF3, 0x89, 0x9A, 0xC2}
+
+// Record is a user record packed inside an aggregated Kinesis record
+type Record struct {
+	PartitionKey    string
+	ExplicitHashKey string
+	Data            []byte
+}
+
+// IsAggregated reports whether data carries the KPL magic prefix and a trailing MD5 checksum
+func IsAggregated(data []byte) bool {
+	return len(data) >= len(Magic)+md5.Size && bytes.HasPrefix(data, Magic)
+}
+
+// Deaggregate unpacks the user records of a KPL aggregated record; the layout is the magic, an
+// AggregatedRecord protobuf message, then the MD5 of that message
+func Deaggregate(data []byte) ([]Record, error) {
+	if !IsAggregated(data) {
+		return nil, errors.New("not a KPL aggregated record")
+	}
+	message := data[len(Magic) : len(data)-md5.Size]
+	checksum := md5.Sum(message)
+	if !bytes.Equal(checksum[:], data[len(data)-md5.Size:]) {
+		return nil, errors.New("aggregated record checksum does not match")
+	}
+
+	var partitionKeys, explicitHashKeys []string
+	var packed [][]byte
+	err := eachField(message, func(number protowire.Number, value []byte) error {
+		switch number {
+		case 1:
+			partitionKeys = append(partitionKeys, string(value))
+		case 2:
+			explicitHashKeys = append(explicitHashKeys, string(value))
+		case 3:
+			packed = append(packed, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(packed))
+	for index, recordMessage := range packed {
+		record, err := decodeRecord(recordMessage, partitionKeys, explicitHashKeys)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", index, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// decodeRecord decodes a Record message, resolving its key table indexes
+func decodeRecord(message []byte, partitionKeys, explicitHashKeys []string) (Record, error) {
+	var record Record
+	partitionIndex, hashIndex := -1, -1
+	err := eachVarintOrBytes(message, func(number protowire.Number, varint uint64, value []byte) error {
+		switch number {
+		case 1:
+			partitionIndex = int(varint)
+		case 2:
+			hashIndex = int(varint)
+		case 3:
+			record.Data = append([]byte(nil), value...)
+		}
+		return nil
+	})
+	if err != nil {
+		return record, err
+	}
+
+	if partitionIndex < 0 || partitionIndex >= len(partitionKeys) {
+		return record, errors.New("partition key index out of range")
+	}
+	record.PartitionKey = partitionKeys[partitionIndex]
+	if hashIndex >= 0 {
+		if hashIndex >= len(explicitHashKeys) {
+			return record, errors.New("explicit hash key index out of range")
+		}
+		record.ExplicitHashKey = explicitHashKeys[hashIndex]
+	}
+	return record, nil
+}
+
+// eachField calls fn for every length-delimited field of a message, skipping the others
+func eachField(message []byte, fn func(number protowire.Number, value []byte) error) error {
+	return eachVarintOrBytes(message, func(number protowire.Number, _ uint64, value []byte) error {
+		if value == nil {
+			return nil
+		}
+		return fn(number, value)
+	})
+}
+
+// eachVarintOrBytes walks a message, calling fn with the varint or bytes value of each field
+func eachVarintOrBytes(message []byte, fn func(number protowire.Number, varint uint64, value []byte) error) error {
+	for len(message) > 0 {
+		number, wireType, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		message = message[n:]
+
+		var varint uint64
+		var value []byte
+		switch wireType {
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(message)
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(message)
+			if value == nil {
+				value = []byte{}
+			}
+		default:
+			n = protowire.ConsumeFieldValue(number, wireType, message)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		message = message[n:]
+
+		if err := fn(number, varint, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Aggregate packs records in the KPL aggregated format
+func Aggregate(records []Record) []byte {
+	var message []byte
+	partitionIndexes := make(map[string]int)
+	hashIndexes := make(map[string]int)
+	var body []byte
+
+	for _, record := range records {
+		partitionIndex, ok := partitionIndexes[record.PartitionKey]
+		if !ok {
+			partitionIndex = len(partitionIndexes)
+			partitionIndexes[record.PartitionKey] = partitionIndex
+			message = protowire.AppendTag(message, 1, protowire.BytesType)
+			message = protowire.AppendString(message, record.PartitionKey)
+		}
+
+		var recordMessage []byte
+		recordMessage = protowire.AppendTag(recordMessage, 1, protowire.VarintType)
+		recordMessage = protowire.AppendVarint(recordMessage, uint64(partitionIndex))
+		if record.ExplicitHashKey != "" {
+			hashIndex, ok := hashIndexes[record.ExplicitHashKey]
+			if !ok {
+				hashIndex = len(hashIndexes)
+				hashIndexes[record.ExplicitHashKey] = hashIndex
+				message = protowire.AppendTag(message, 2, protowire.BytesType)
+				message = protowire.AppendString(message, record.ExplicitHashKey)
+			}
+			recordMessage = protowire.AppendTag(recordMessage, 2, protowire.VarintType)
+			recordMessage = protowire.AppendVarint(recordMessage, uint64(hashIndex))
+		}
+		recordMessage = protowire.AppendTag(recordMessage, 3, protowire.BytesType)
+		recordMessage = protowire.AppendBytes(recordMessage, record.Data)
+
+		body = protowire.AppendTag(body, 3, protowire.BytesType)
+		body = protowire.AppendBytes(body, recordMessage)
+	}
+	message = append(message, body...)
+
+	checksum := md5.Sum(message)
+	data := append(append([]byte(nil), Magic...), message...)
+	return append(data, checksum[:]...)
+}
@@ -0,0 +1,42 @@
+package kpl
+
+import (
+	"testing"
+)
+
+func TestDeaggregate(t *testing.T) {
+	records := []Record{
+		{PartitionKey: "a", Data: []byte(`{"n":1}`)},
+		{PartitionKey: "b", ExplicitHashKey: "123", Data: []byte("two")},
+		{PartitionKey: "a", Data: []byte{}},
+	}
+	data := Aggregate(records)
+
+	if !IsAggregated(data) {
+		t.Fatal("Expected aggregated data to be detected")
+	}
+	result, err := Deaggregate(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(result))
+	}
+	for i := range records {
+		if result[i].PartitionKey != records[i].PartitionKey || result[i].ExplicitHashKey != records[i].ExplicitHashKey || string(result[i].Data) != string(records[i].Data) {
+			t.Errorf("Record %d: expected %+v, got %+v", i, records[i], result[i])
+		}
+	}
+}
+
+func TestDeaggregate_Errors(t *testing.T) {
+	if _, err := Deaggregate([]byte(`{"plain":"json"}`)); err == nil {
+		t.Error("Expected error for plain data")
+	}
+
+	data := Aggregate([]Record{{PartitionKey: "a", Data: []byte("x")}})
+	data[len(Magic)+1] ^= 0xFF
+	if _, err := Deaggregate(data); err == nil {
+		t.Error("Expected checksum error for corrupted data")
+	}
+}
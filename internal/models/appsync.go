@@ -0,0 +1,22 @@
+package models
+
+// AppSyncEcho is the GraphQL result returned by the AppSync resolver echo
+type AppSyncEcho struct {
+	FieldName        string                 `json:"fieldName"`
+	ParentTypeName   string                 `json:"parentTypeName"`
+	Arguments        map[string]interface{} `json:"arguments"`
+	Identity         interface{}            `json:"identity"`
+	Source           interface{}            `json:"source"`
+	SelectionSetList []string               `json:"selectionSetList"`
+	Headers          map[string]string      `json:"headers"`
+	Prev             interface{}            `json:"prev"`
+	BatchIndex       *int                   `json:"batchIndex,omitempty"`
+}
+
+// AppSyncBatchItem is one result of a batch invocation; AppSync raises a field error for items that carry an errorType
+type AppSyncBatchItem struct {
+	Data         interface{} `json:"data"`
+	ErrorMessage string      `json:"errorMessage,omitempty"`
+	ErrorType    string      `json:"errorType,omitempty"`
+	ErrorInfo    interface{} `json:"errorInfo,omitempty"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Bin is an isolated set of recorded requests, captured from /b/{token}/...
+type Bin struct {
+	Token       string    `json:"token"`
+	Name        string    `json:"name,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	MaxRequests int       `json:"maxRequests"`
+}
+
+// CapturePath returns the path prefix whose requests are recorded into the bin
+func (b *Bin) CapturePath() string {
+	return "/b/" + b.Token
+}
+
+// BinResponse describes a bin and, when listed, its requests newest first
+type BinResponse struct {
+	*Bin
+	CapturePath string             `json:"capturePath"`
+	Records     []*RecordedRequest `json:"records,omitempty"`
+	Count       int                `json:"count"`
+}
@@ -1,25 +1,86 @@
 package models
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"strings"
 	"time"
 )
 
 // EchoRequest represents the incoming HTTP request data
 type EchoRequest struct {
-	Method      string            `json:"method"`
-	Path        string            `json:"path"`
-	Headers     map[string]string `json:"headers"`
-	QueryParams map[string]string `json:"queryParams"`
-	Body        string            `json:"body,omitempty"`
-	Timestamp   string            `json:"timestamp"`
+	Method                 string            `json:"method"`
+	Path                   string            `json:"path"`
+	Headers                map[string]string `json:"headers"`
+	QueryParams            map[string]string `json:"queryParams"`
+	Body                   string            `json:"body,omitempty"`
+	IsBase64Encoded        bool              `json:"isBase64Encoded,omitempty"`
+	DecodedContentEncoding string            `json:"decodedContentEncoding,omitempty"`
+	ParsedBody             interface{}       `json:"parsedBody,omitempty"`
+	ParseError             string            `json:"parseError,omitempty"`
+	Timestamp              string            `json:"timestamp"`
+
+	// wireBody keeps the body as received once content decoding has replaced Body; signatures cover these bytes
+	wireBody []byte
 }
 
 // EchoResponse represents the response containing the echo of the request
 type EchoResponse struct {
-	Request     EchoRequest `json:"request"`
-	Message     string      `json:"message"`
-	ProcessedAt string      `json:"processedAt"`
+	Request     EchoRequest       `json:"request"`
+	Message     string            `json:"message"`
+	ProcessedAt string            `json:"processedAt"`
+	Token       *TokenInfo        `json:"token,omitempty"`
+	Webhook     *WebhookSignature `json:"webhook,omitempty"`
+	SigV4       *SigV4Info        `json:"sigv4,omitempty"`
+	FunctionURL *FunctionURLInfo  `json:"functionUrl,omitempty"`
+}
+
+// StreamEvent is a single record of a streamed echo
+type StreamEvent struct {
+	Sequence  int          `json:"sequence"`
+	Request   *EchoRequest `json:"request"`
+	Timestamp string       `json:"timestamp"`
+}
+
+// WebSocketMessage is the echo posted back to the sender of a WebSocket message
+type WebSocketMessage struct {
+	ConnectionID    string      `json:"connectionId"`
+	RouteKey        string      `json:"routeKey"`
+	Body            string      `json:"body"`
+	IsBase64Encoded bool        `json:"isBase64Encoded,omitempty"`
+	ParsedBody      interface{} `json:"parsedBody,omitempty"`
+	RequestContext  interface{} `json:"requestContext"`
+	Timestamp       string      `json:"timestamp"`
+}
+
+// TokenInfo represents a decoded bearer token found in the Authorization header
+type TokenInfo struct {
+	Header    map[string]interface{} `json:"header,omitempty"`
+	Claims    map[string]interface{} `json:"claims,omitempty"`
+	Expiry    *TokenExpiry           `json:"expiry,omitempty"`
+	NotBefore *TokenNotBefore        `json:"notBefore,omitempty"`
+	Signature TokenSignature         `json:"signature"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// TokenExpiry reports the exp claim of a token
+type TokenExpiry struct {
+	At      string `json:"at"`
+	Expired bool   `json:"expired"`
+}
+
+// TokenNotBefore reports the nbf claim of a token
+type TokenNotBefore struct {
+	At     string `json:"at"`
+	Active bool   `json:"active"`
+}
+
+// TokenSignature reports the outcome of token signature verification
+type TokenSignature struct {
+	Algorithm string `json:"algorithm,omitempty"`
+	KeyID     string `json:"keyId,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
 }
 
 // ErrorResponse represents an error response
@@ -29,6 +90,65 @@ type ErrorResponse struct {
 	Timestamp string `json:"timestamp"`
 }
 
+// WebhookSignature reports the outcome of webhook signature verification
+type WebhookSignature struct {
+	Scheme    string `json:"scheme"`
+	Header    string `json:"header"`
+	Verified  bool   `json:"verified"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// SigV4Info reports how the server computes the AWS Signature Version 4 of the request
+type SigV4Info struct {
+	Source           string   `json:"source"`
+	AccessKeyID      string   `json:"accessKeyId,omitempty"`
+	CredentialScope  string   `json:"credentialScope,omitempty"`
+	Region           string   `json:"region,omitempty"`
+	Service          string   `json:"service,omitempty"`
+	RequestTime      string   `json:"requestTime,omitempty"`
+	ExpiresAt        string   `json:"expiresAt,omitempty"`
+	Expired          bool     `json:"expired"`
+	SignedHeaders    []string `json:"signedHeaders,omitempty"`
+	Signature        string   `json:"signature,omitempty"`
+	PayloadHash      string   `json:"payloadHash,omitempty"`
+	CanonicalRequest string   `json:"canonicalRequest,omitempty"`
+	StringToSign     string   `json:"stringToSign,omitempty"`
+	Issues           []string `json:"issues,omitempty"`
+	Status           string   `json:"status"`
+	Error            string   `json:"error,omitempty"`
+}
+
+// FunctionURLInfo reports the request context of a Lambda Function URL invocation
+type FunctionURLInfo struct {
+	URLID      string              `json:"urlId"`
+	DomainName string              `json:"domainName"`
+	RequestID  string              `json:"requestId"`
+	Time       string              `json:"time,omitempty"`
+	HTTP       FunctionURLHTTP     `json:"http"`
+	RawQuery   string              `json:"rawQueryString,omitempty"`
+	Cookies    []string            `json:"cookies,omitempty"`
+	IAM        *FunctionURLIAMAuth `json:"iam,omitempty"`
+}
+
+// FunctionURLHTTP is the HTTP description of a Function URL request
+type FunctionURLHTTP struct {
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Protocol  string `json:"protocol"`
+	SourceIP  string `json:"sourceIp"`
+	UserAgent string `json:"userAgent"`
+}
+
+// FunctionURLIAMAuth identifies the caller of a Function URL with AuthType AWS_IAM
+type FunctionURLIAMAuth struct {
+	AccessKey string `json:"accessKey"`
+	AccountID string `json:"accountId"`
+	CallerID  string `json:"callerId"`
+	UserARN   string `json:"userArn"`
+	UserID    string `json:"userId"`
+}
+
 // NewEchoRequest creates a new EchoRequest with current timestamp
 func NewEchoRequest(method, path string, headers, queryParams map[string]string, body string) *EchoRequest {
 	return &EchoRequest{
@@ -41,6 +161,46 @@ func NewEchoRequest(method, path string, headers, queryParams map[string]string,
 	}
 }
 
+// Header returns the value of the named header, matching the name case-insensitively
+func (r *EchoRequest) Header(name string) string {
+	value, _ := HeaderValue(r.Headers, name)
+	return value
+}
+
+// HeaderValue looks up a header case-insensitively, reporting whether it is present
+func HeaderValue(headers map[string]string, name string) (string, bool) {
+	if value, ok := headers[name]; ok {
+		return value, true
+	}
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// RawBody returns the body bytes, decoding base64 bodies delivered by API Gateway
+func (r *EchoRequest) RawBody() ([]byte, error) {
+	if r.IsBase64Encoded {
+		return base64.StdEncoding.DecodeString(r.Body)
+	}
+	return []byte(r.Body), nil
+}
+
+// SetWireBody records the body as received before Body is replaced with its decoded form
+func (r *EchoRequest) SetWireBody(body []byte) {
+	r.wireBody = body
+}
+
+// SignedBody returns the body bytes as the client sent them, before any content decoding
+func (r *EchoRequest) SignedBody() ([]byte, error) {
+	if r.wireBody != nil {
+		return r.wireBody, nil
+	}
+	return r.RawBody()
+}
+
 // NewEchoResponse creates a new EchoResponse with current processed timestamp
 func NewEchoResponse(request *EchoRequest, message string) *EchoResponse {
 	return &EchoResponse{
@@ -50,6 +210,15 @@ func NewEchoResponse(request *EchoRequest, message string) *EchoResponse {
 	}
 }
 
+// NewStreamEvent creates a new StreamEvent with current timestamp
+func NewStreamEvent(sequence int, request *EchoRequest) *StreamEvent {
+	return &StreamEvent{
+		Sequence:  sequence,
+		Request:   request,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+}
+
 // NewErrorResponse creates a new ErrorResponse with current timestamp
 func NewErrorResponse(error, message string) *ErrorResponse {
 	return &ErrorResponse{
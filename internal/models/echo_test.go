@@ -137,4 +137,23 @@ func TestErrorResponseToJSON(t *testing.T) {
 	if parsed.Message != resp.Message {
 		t.Errorf("Expected message %s, got %s", resp.Message, parsed.Message)
 	}
-}
\ No newline at end of file
+}
+
+func TestEchoRequestHeader(t *testing.T) {
+	req := NewEchoRequest("GET", "/", map[string]string{"x-request-id": "abc"}, nil, "")
+
+	if req.Header("X-Request-Id") != "abc" {
+		t.Errorf("Expected case-insensitive header match, got %q", req.Header("X-Request-Id"))
+	}
+	if req.Header("Missing") != "" {
+		t.Errorf("Expected empty value for missing header, got %q", req.Header("Missing"))
+	}
+
+	// HeaderValue tells an empty header from a missing one
+	if value, ok := HeaderValue(map[string]string{"X-Empty": ""}, "x-empty"); !ok || value != "" {
+		t.Errorf("Expected an empty present header, got %q %v", value, ok)
+	}
+	if _, ok := HeaderValue(req.Headers, "Missing"); ok {
+		t.Error("Expected a missing header to be reported absent")
+	}
+}
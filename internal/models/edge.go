@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// EdgeEcho is the echo of the request a Lambda@Edge function received
+type EdgeEcho struct {
+	EventType              string      `json:"eventType"`
+	DistributionID         string      `json:"distributionId"`
+	DistributionDomainName string      `json:"distributionDomainName"`
+	RequestID              string      `json:"requestId"`
+	ClientIP               string      `json:"clientIp"`
+	Method                 string      `json:"method"`
+	URI                    string      `json:"uri"`
+	Querystring            string      `json:"querystring"`
+	Headers                interface{} `json:"headers"`
+	Origin                 interface{} `json:"origin,omitempty"`
+	Body                   interface{} `json:"body,omitempty"`
+	Omitted                []string    `json:"omitted,omitempty"`
+	ProcessedAt            string      `json:"processedAt"`
+}
+
+// NewEdgeEcho creates a new EdgeEcho with current processed timestamp
+func NewEdgeEcho(eventType string) *EdgeEcho {
+	return &EdgeEcho{
+		EventType:   eventType,
+		ProcessedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// EventEcho is the normalized echo of a record delivered by an event source
+type EventEcho struct {
+	Source      string                 `json:"source"`
+	DetailType  string                 `json:"detailType,omitempty"`
+	ID          string                 `json:"id,omitempty"`
+	Time        string                 `json:"time,omitempty"`
+	Resources   []string               `json:"resources,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	Body        string                 `json:"body,omitempty"`
+	Detail      interface{}            `json:"detail,omitempty"`
+	ParseError  string                 `json:"parseError,omitempty"`
+	ProcessedAt string                 `json:"processedAt"`
+}
+
+// NewEventEcho creates a new EventEcho for source with current processed timestamp
+func NewEventEcho(source string) *EventEcho {
+	return &EventEcho{
+		Source:      source,
+		Attributes:  make(map[string]interface{}),
+		ProcessedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// RecordedRequest is a request kept by the recorder
+type RecordedRequest struct {
+	ID         string       `json:"id"`
+	RecordedAt time.Time    `json:"recordedAt"`
+	Request    *EchoRequest `json:"request"`
+}
+
+// HistoryResponse lists recorded requests, newest first
+type HistoryResponse struct {
+	Records []*RecordedRequest `json:"records"`
+	Count   int                `json:"count"`
+}
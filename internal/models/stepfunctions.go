@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// TaskEcho is the output of the Step Functions task echo
+type TaskEcho struct {
+	Input       interface{} `json:"input"`
+	Context     interface{} `json:"context,omitempty"`
+	RetryCount  *int        `json:"retryCount,omitempty"`
+	ProcessedAt string      `json:"processedAt"`
+}
+
+// NewTaskEcho creates a new TaskEcho with current processed timestamp
+func NewTaskEcho(input, context interface{}) *TaskEcho {
+	return &TaskEcho{
+		Input:       input,
+		Context:     context,
+		ProcessedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,192 @@
+package recorder
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/internal/sigv4"
+)
+
+// ErrBinNotFound is returned when no bin has the token or the bin has expired
+var ErrBinNotFound = errors.New("bin not found")
+
+// ErrTooManyBins is returned when a store cannot hold another bin
+var ErrTooManyBins = errors.New("too many active bins")
+
+// BinStore keeps bins and the requests captured into each of them
+type BinStore interface {
+	CreateBin(ctx context.Context, bin *models.Bin) error
+	// GetBin returns ErrBinNotFound for missing and expired bins
+	GetBin(ctx context.Context, token string) (*models.Bin, error)
+	// PutRecord adds record to the bin, dropping its oldest records beyond the bin's MaxRequests
+	PutRecord(ctx context.Context, token string, record *models.RecordedRequest) error
+	// ListRecords returns the bin's records matching filter, newest first
+	ListRecords(ctx context.Context, token string, filter Filter) ([]*models.RecordedRequest, error)
+}
+
+// BinLimits bounds the bins clients create; requests may ask for less, never more
+type BinLimits struct {
+	TTL         time.Duration
+	MaxRequests int
+}
+
+// NewBin creates a bin with a random token expiring ttl after createdAt
+func NewBin(name string, createdAt time.Time, ttl time.Duration, maxRequests int) *models.Bin {
+	token := make([]byte, 16)
+	rand.Read(token)
+	return &models.Bin{
+		Token:       hex.EncodeToString(token),
+		Name:        name,
+		CreatedAt:   createdAt.UTC(),
+		ExpiresAt:   createdAt.Add(ttl).UTC(),
+		MaxRequests: maxRequests,
+	}
+}
+
+// BinsFromEnv creates the bin store selected by BIN_STORE (memory, the default, or dynamodb)
+// and reads the limits from BIN_TTL and BIN_MAX_REQUESTS
+func BinsFromEnv() (BinStore, BinLimits, error) {
+	limits := BinLimits{TTL: 24 * time.Hour, MaxRequests: 100}
+	if value := os.Getenv("BIN_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return nil, limits, fmt.Errorf("invalid BIN_TTL %q", value)
+		}
+		limits.TTL = parsed
+	}
+	if value := os.Getenv("BIN_MAX_REQUESTS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return nil, limits, fmt.Errorf("invalid BIN_MAX_REQUESTS %q", value)
+		}
+		limits.MaxRequests = parsed
+	}
+
+	switch kind := os.Getenv("BIN_STORE"); kind {
+	case "", "memory":
+		maxBins := 1000
+		if value := os.Getenv("BIN_LIMIT"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed <= 0 {
+				return nil, limits, fmt.Errorf("invalid BIN_LIMIT %q", value)
+			}
+			maxBins = parsed
+		}
+		return NewMemoryBinStore(maxBins), limits, nil
+	case "dynamodb":
+		table := os.Getenv("BIN_TABLE")
+		if table == "" {
+			return nil, limits, errors.New("BIN_TABLE is required for the dynamodb bin store")
+		}
+		signer := sigv4.NewSigner(sigv4.AWSCredentialsFromEnv(), os.Getenv("AWS_REGION"), "dynamodb")
+		return NewDynamoDBBinStore(NewDynamoDBClient(signer), table), limits, nil
+	default:
+		return nil, limits, fmt.Errorf("unknown BIN_STORE %q", kind)
+	}
+}
+
+// memoryBin is a bin with its records, oldest first
+type memoryBin struct {
+	bin     *models.Bin
+	records []*models.RecordedRequest
+}
+
+// MemoryBinStore keeps up to maxBins unexpired bins in memory
+type MemoryBinStore struct {
+	mu      sync.Mutex
+	maxBins int
+	bins    map[string]*memoryBin
+	now     func() time.Time
+}
+
+// NewMemoryBinStore creates a new MemoryBinStore holding up to maxBins bins
+func NewMemoryBinStore(maxBins int) *MemoryBinStore {
+	return &MemoryBinStore{
+		maxBins: maxBins,
+		bins:    make(map[string]*memoryBin),
+		now:     time.Now,
+	}
+}
+
+// CreateBin adds bin after dropping the expired bins
+func (m *MemoryBinStore) CreateBin(ctx context.Context, bin *models.Bin) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for token, b := range m.bins {
+		if !now.Before(b.bin.ExpiresAt) {
+			delete(m.bins, token)
+		}
+	}
+	if len(m.bins) >= m.maxBins {
+		return ErrTooManyBins
+	}
+	m.bins[bin.Token] = &memoryBin{bin: bin}
+	return nil
+}
+
+// GetBin returns the bin with token
+func (m *MemoryBinStore) GetBin(ctx context.Context, token string) (*models.Bin, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, err := m.lookup(token)
+	if err != nil {
+		return nil, err
+	}
+	return b.bin, nil
+}
+
+// PutRecord appends record to the bin, evicting its oldest record when the bin is full
+func (m *MemoryBinStore) PutRecord(ctx context.Context, token string, record *models.RecordedRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, err := m.lookup(token)
+	if err != nil {
+		return err
+	}
+	if len(b.records) >= b.bin.MaxRequests {
+		b.records = append(b.records[:0], b.records[len(b.records)-b.bin.MaxRequests+1:]...)
+	}
+	b.records = append(b.records, record)
+	return nil
+}
+
+// ListRecords returns the bin's records matching filter, newest first
+func (m *MemoryBinStore) ListRecords(ctx context.Context, token string, filter Filter) ([]*models.RecordedRequest, error) {
+	m.mu.Lock()
+	b, err := m.lookup(token)
+	var records []*models.RecordedRequest
+	if err == nil {
+		records = append(records, b.records...)
+	}
+	m.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	return filter.Select(records), nil
+}
+
+// lookup returns the unexpired bin with token, deleting it once expired; m.mu must be held
+func (m *MemoryBinStore) lookup(token string) (*memoryBin, error) {
+	b, ok := m.bins[token]
+	if !ok {
+		return nil, ErrBinNotFound
+	}
+	if !m.now().Before(b.bin.ExpiresAt) {
+		delete(m.bins, token)
+		return nil, ErrBinNotFound
+	}
+	return b, nil
+}
@@ -0,0 +1,115 @@
+package recorder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// exerciseBinStore runs the BinStore contract against store, whose clock is set through setNow
+func exerciseBinStore(t *testing.T, store BinStore, setNow func(time.Time)) {
+	t.Helper()
+	ctx := context.Background()
+	setNow(baseTime)
+
+	bin := NewBin("team-a", baseTime, time.Hour, 2)
+	other := NewBin("team-b", baseTime, time.Hour, 2)
+	for _, token := range []string{bin.Token, other.Token} {
+		if len(token) != 32 {
+			t.Fatalf("Expected a 32 character token, got %q", token)
+		}
+	}
+	if err := store.CreateBin(ctx, bin); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	store.CreateBin(ctx, other)
+
+	got, err := store.GetBin(ctx, bin.Token)
+	if err != nil || got.Name != "team-a" || got.MaxRequests != 2 || !got.ExpiresAt.Equal(baseTime.Add(time.Hour)) {
+		t.Errorf("Expected the bin, got %+v (%v)", got, err)
+	}
+
+	// The oldest record is dropped beyond MaxRequests, and other bins are unaffected
+	for _, record := range testRecords() {
+		if err := store.PutRecord(ctx, bin.Token, record); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	store.PutRecord(ctx, other.Token, testRecord("9", "GET", "/other", nil, 0))
+
+	records, err := store.ListRecords(ctx, bin.Token, Filter{})
+	if err != nil || len(records) != 2 || records[0].ID != "3" || records[1].ID != "2" {
+		t.Errorf("Expected records 3 and 2, got %v (%v)", records, err)
+	}
+	if records, _ := store.ListRecords(ctx, other.Token, Filter{}); len(records) != 1 || records[0].Request.Path != "/other" {
+		t.Errorf("Expected only the other bin's record, got %v", records)
+	}
+
+	if _, err := store.GetBin(ctx, "missing"); err != ErrBinNotFound {
+		t.Errorf("Expected ErrBinNotFound, got %v", err)
+	}
+	if err := store.PutRecord(ctx, "missing", testRecord("4", "GET", "/", nil, 0)); err != ErrBinNotFound {
+		t.Errorf("Expected ErrBinNotFound, got %v", err)
+	}
+
+	setNow(baseTime.Add(time.Hour))
+	if _, err := store.GetBin(ctx, bin.Token); err != ErrBinNotFound {
+		t.Errorf("Expected the bin to expire, got %v", err)
+	}
+	if _, err := store.ListRecords(ctx, bin.Token, Filter{}); err != ErrBinNotFound {
+		t.Errorf("Expected ErrBinNotFound after expiry, got %v", err)
+	}
+}
+
+func TestMemoryBinStore(t *testing.T) {
+	store := NewMemoryBinStore(10)
+	exerciseBinStore(t, store, func(now time.Time) { store.now = func() time.Time { return now } })
+
+	// Expired bins make room for new ones
+	store = NewMemoryBinStore(1)
+	store.now = func() time.Time { return baseTime }
+	store.CreateBin(context.Background(), NewBin("", baseTime, time.Minute, 1))
+	if err := store.CreateBin(context.Background(), NewBin("", baseTime, time.Minute, 1)); err != ErrTooManyBins {
+		t.Errorf("Expected ErrTooManyBins, got %v", err)
+	}
+	store.now = func() time.Time { return baseTime.Add(time.Minute) }
+	if err := store.CreateBin(context.Background(), NewBin("", baseTime, time.Hour, 1)); err != nil {
+		t.Errorf("Expected the expired bin to be replaced, got %v", err)
+	}
+}
+
+func TestDynamoDBBinStore(t *testing.T) {
+	api := NewMemoryDynamoDB()
+	api.DefineTable("bins", "bin", "id")
+	store := NewDynamoDBBinStore(api, "bins")
+	exerciseBinStore(t, store, func(now time.Time) { store.now = func() time.Time { return now } })
+
+	// Bin records carry the bin's expiry for the table TTL
+	items, _ := api.Scan(context.Background(), "bins")
+	for _, item := range items {
+		if expiresAt := item["expiresAt"].Number(); expiresAt != "1704168245" {
+			t.Errorf("Expected expiresAt 1704168245, got %s", expiresAt)
+		}
+	}
+}
+
+func TestBinsFromEnv(t *testing.T) {
+	t.Setenv("BIN_STORE", "")
+	t.Setenv("BIN_TTL", "30m")
+	store, limits, err := BinsFromEnv()
+	if _, ok := store.(*MemoryBinStore); !ok || err != nil || limits.TTL != 30*time.Minute || limits.MaxRequests != 100 {
+		t.Errorf("Expected a memory store with a 30m TTL, got %T %+v (%v)", store, limits, err)
+	}
+
+	t.Setenv("BIN_MAX_REQUESTS", "0")
+	if _, _, err := BinsFromEnv(); err == nil {
+		t.Error("Expected an error for BIN_MAX_REQUESTS=0")
+	}
+
+	t.Setenv("BIN_MAX_REQUESTS", "")
+	t.Setenv("BIN_STORE", "dynamodb")
+	t.Setenv("BIN_TABLE", "")
+	if _, _, err := BinsFromEnv(); err == nil {
+		t.Error("Expected an error without BIN_TABLE")
+	}
+}
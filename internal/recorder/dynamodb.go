@@ -0,0 +1,532 @@
+package recorder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/internal/sigv4"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Item is a DynamoDB item in the wire format
+type Item map[string]events.DynamoDBAttributeValue
+
+// DynamoDBAPI is the subset of DynamoDB the store uses
+type DynamoDBAPI interface {
+	PutItem(ctx context.Context, table string, item Item) error
+	// GetItem returns nil when the item does not exist
+	GetItem(ctx context.Context, table string, key Item) (Item, error)
+	Scan(ctx context.Context, table string) ([]Item, error)
+	// Query returns the items whose partition key attribute equals key's only attribute, in sort key order
+	Query(ctx context.Context, table string, key Item) ([]Item, error)
+	DeleteItem(ctx context.Context, table string, key Item) error
+}
+
+// DynamoDBStore keeps records in a DynamoDB table with the string partition key id. The request is
+// stored as JSON; when ttl is set, expiresAt holds the epoch second for the table's TTL to remove it
+type DynamoDBStore struct {
+	api   DynamoDBAPI
+	table string
+	ttl   time.Duration
+}
+
+// NewDynamoDBStore creates a new DynamoDBStore on table
+func NewDynamoDBStore(api DynamoDBAPI, table string, ttl time.Duration) *DynamoDBStore {
+	return &DynamoDBStore{api: api, table: table, ttl: ttl}
+}
+
+// Put writes record as an item
+func (d *DynamoDBStore) Put(ctx context.Context, record *models.RecordedRequest) error {
+	request, err := json.Marshal(record.Request)
+	if err != nil {
+		return err
+	}
+
+	item := Item{
+		"id":         events.NewStringAttribute(record.ID),
+		"recordedAt": events.NewStringAttribute(record.RecordedAt.Format(time.RFC3339Nano)),
+		"request":    events.NewStringAttribute(string(request)),
+	}
+	if d.ttl > 0 {
+		expiresAt := record.RecordedAt.Add(d.ttl).Unix()
+		item["expiresAt"] = events.NewNumberAttribute(strconv.FormatInt(expiresAt, 10))
+	}
+	return d.api.PutItem(ctx, d.table, item)
+}
+
+// Get reads the item with id
+func (d *DynamoDBStore) Get(ctx context.Context, id string) (*models.RecordedRequest, error) {
+	item, err := d.api.GetItem(ctx, d.table, recordKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrNotFound
+	}
+	return recordFromItem(item)
+}
+
+// List scans the table and returns the records matching filter, newest first
+func (d *DynamoDBStore) List(ctx context.Context, filter Filter) ([]*models.RecordedRequest, error) {
+	items, err := d.api.Scan(ctx, d.table)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*models.RecordedRequest, 0, len(items))
+	for _, item := range items {
+		if record, err := recordFromItem(item); err == nil {
+			records = append(records, record)
+		}
+	}
+	return filter.Select(records), nil
+}
+
+// Clear deletes every item
+func (d *DynamoDBStore) Clear(ctx context.Context) error {
+	items, err := d.api.Scan(ctx, d.table)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := d.api.DeleteItem(ctx, d.table, Item{"id": item["id"]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordKey returns the key of the item holding id
+func recordKey(id string) Item {
+	return Item{"id": events.NewStringAttribute(id)}
+}
+
+// recordFromItem decodes an item written by Put
+func recordFromItem(item Item) (*models.RecordedRequest, error) {
+	id, ok := stringAttribute(item, "id")
+	if !ok {
+		return nil, fmt.Errorf("item has no id")
+	}
+	recordedAtValue, _ := stringAttribute(item, "recordedAt")
+	recordedAt, err := time.Parse(time.RFC3339Nano, recordedAtValue)
+	if err != nil {
+		return nil, fmt.Errorf("item %s has an invalid recordedAt: %w", id, err)
+	}
+	requestValue, _ := stringAttribute(item, "request")
+	request := &models.EchoRequest{}
+	if err := json.Unmarshal([]byte(requestValue), request); err != nil {
+		return nil, fmt.Errorf("item %s has an invalid request: %w", id, err)
+	}
+	return &models.RecordedRequest{ID: id, RecordedAt: recordedAt, Request: request}, nil
+}
+
+// stringAttribute returns the string attribute name of item
+func stringAttribute(item Item, name string) (string, bool) {
+	attribute, ok := item[name]
+	if !ok || attribute.DataType() != events.DataTypeString {
+		return "", false
+	}
+	return attribute.String(), true
+}
+
+// binMetadataID is the sort key of the item describing a bin; it sorts before every record id
+const binMetadataID = "#bin"
+
+// DynamoDBBinStore keeps bins in a DynamoDB table with the string partition key bin and the string sort
+// key id. A bin is the item (token, #bin) and each of its records the item (token, record id), so one
+// Query reads a bin without touching the others; every item carries the bin's expiry in expiresAt for
+// the table's TTL to remove it
+type DynamoDBBinStore struct {
+	api   DynamoDBAPI
+	table string
+	now   func() time.Time
+}
+
+// NewDynamoDBBinStore creates a new DynamoDBBinStore on table
+func NewDynamoDBBinStore(api DynamoDBAPI, table string) *DynamoDBBinStore {
+	return &DynamoDBBinStore{api: api, table: table, now: time.Now}
+}
+
+// CreateBin writes the bin item
+func (d *DynamoDBBinStore) CreateBin(ctx context.Context, bin *models.Bin) error {
+	return d.api.PutItem(ctx, d.table, Item{
+		"bin":         events.NewStringAttribute(bin.Token),
+		"id":          events.NewStringAttribute(binMetadataID),
+		"name":        events.NewStringAttribute(bin.Name),
+		"createdAt":   events.NewStringAttribute(bin.CreatedAt.Format(time.RFC3339Nano)),
+		"expiresAt":   events.NewNumberAttribute(strconv.FormatInt(bin.ExpiresAt.Unix(), 10)),
+		"maxRequests": events.NewNumberAttribute(strconv.Itoa(bin.MaxRequests)),
+	})
+}
+
+// GetBin reads the bin item
+func (d *DynamoDBBinStore) GetBin(ctx context.Context, token string) (*models.Bin, error) {
+	item, err := d.api.GetItem(ctx, d.table, binKey(token, binMetadataID))
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrBinNotFound
+	}
+	return d.binFromItem(token, item)
+}
+
+// PutRecord writes record and deletes the bin's oldest records beyond its MaxRequests
+func (d *DynamoDBBinStore) PutRecord(ctx context.Context, token string, record *models.RecordedRequest) error {
+	bin, err := d.GetBin(ctx, token)
+	if err != nil {
+		return err
+	}
+	request, err := json.Marshal(record.Request)
+	if err != nil {
+		return err
+	}
+
+	err = d.api.PutItem(ctx, d.table, Item{
+		"bin":        events.NewStringAttribute(token),
+		"id":         events.NewStringAttribute(record.ID),
+		"recordedAt": events.NewStringAttribute(record.RecordedAt.Format(time.RFC3339Nano)),
+		"request":    events.NewStringAttribute(string(request)),
+		"expiresAt":  events.NewNumberAttribute(strconv.FormatInt(bin.ExpiresAt.Unix(), 10)),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, records, err := d.query(ctx, token)
+	if err != nil {
+		return err
+	}
+	for _, old := range (Filter{}).Select(records)[min(len(records), bin.MaxRequests):] {
+		if err := d.api.DeleteItem(ctx, d.table, binKey(token, old.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListRecords returns the bin's records matching filter, newest first
+func (d *DynamoDBBinStore) ListRecords(ctx context.Context, token string, filter Filter) ([]*models.RecordedRequest, error) {
+	bin, records, err := d.query(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if bin == nil {
+		return nil, ErrBinNotFound
+	}
+	return filter.Select(records), nil
+}
+
+// query reads the bin with token and its records in one Query; bin is nil when it is missing or expired
+func (d *DynamoDBBinStore) query(ctx context.Context, token string) (*models.Bin, []*models.RecordedRequest, error) {
+	items, err := d.api.Query(ctx, d.table, Item{"bin": events.NewStringAttribute(token)})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var bin *models.Bin
+	records := make([]*models.RecordedRequest, 0, len(items))
+	for _, item := range items {
+		if id, _ := stringAttribute(item, "id"); id == binMetadataID {
+			if bin, err = d.binFromItem(token, item); errors.Is(err, ErrBinNotFound) {
+				bin = nil
+			} else if err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if record, err := recordFromItem(item); err == nil {
+			records = append(records, record)
+		}
+	}
+	return bin, records, nil
+}
+
+// binFromItem decodes a bin item; TTL deletes lazily, so an expired bin is reported as missing
+func (d *DynamoDBBinStore) binFromItem(token string, item Item) (*models.Bin, error) {
+	createdAtValue, _ := stringAttribute(item, "createdAt")
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtValue)
+	if err != nil {
+		return nil, fmt.Errorf("bin %s has an invalid createdAt: %w", token, err)
+	}
+	expiresAt, err := numberAttribute(item, "expiresAt")
+	if err != nil {
+		return nil, fmt.Errorf("bin %s has an invalid expiresAt: %w", token, err)
+	}
+	maxRequests, err := numberAttribute(item, "maxRequests")
+	if err != nil {
+		return nil, fmt.Errorf("bin %s has an invalid maxRequests: %w", token, err)
+	}
+	name, _ := stringAttribute(item, "name")
+
+	bin := &models.Bin{
+		Token:       token,
+		Name:        name,
+		CreatedAt:   createdAt,
+		ExpiresAt:   time.Unix(expiresAt, 0).UTC(),
+		MaxRequests: int(maxRequests),
+	}
+	if !d.now().Before(bin.ExpiresAt) {
+		return nil, ErrBinNotFound
+	}
+	return bin, nil
+}
+
+// binKey returns the key of the item id in the bin with token
+func binKey(token, id string) Item {
+	return Item{"bin": events.NewStringAttribute(token), "id": events.NewStringAttribute(id)}
+}
+
+// numberAttribute returns the number attribute name of item as an integer
+func numberAttribute(item Item, name string) (int64, error) {
+	attribute, ok := item[name]
+	if !ok || attribute.DataType() != events.DataTypeNumber {
+		return 0, fmt.Errorf("missing number attribute %s", name)
+	}
+	return attribute.Integer()
+}
+
+// DynamoDBClient calls the DynamoDB JSON API with SigV4 signed requests
+type DynamoDBClient struct {
+	http     *http.Client
+	signer   *sigv4.Signer
+	endpoint string
+}
+
+// NewDynamoDBClient creates a new DynamoDBClient for the signer's region
+func NewDynamoDBClient(signer *sigv4.Signer) *DynamoDBClient {
+	return &DynamoDBClient{
+		http:     &http.Client{Timeout: 10 * time.Second},
+		signer:   signer,
+		endpoint: "https://dynamodb." + signer.Region() + ".amazonaws.com/",
+	}
+}
+
+// PutItem writes item to table
+func (c *DynamoDBClient) PutItem(ctx context.Context, table string, item Item) error {
+	return c.call(ctx, "PutItem", map[string]interface{}{"TableName": table, "Item": item}, nil)
+}
+
+// GetItem reads the item with key from table, returning nil when it does not exist
+func (c *DynamoDBClient) GetItem(ctx context.Context, table string, key Item) (Item, error) {
+	var output struct {
+		Item Item `json:"Item"`
+	}
+	err := c.call(ctx, "GetItem", map[string]interface{}{"TableName": table, "Key": key, "ConsistentRead": true}, &output)
+	return output.Item, err
+}
+
+// Scan reads every item of table, following pagination
+func (c *DynamoDBClient) Scan(ctx context.Context, table string) ([]Item, error) {
+	return c.paginate(ctx, "Scan", map[string]interface{}{"TableName": table})
+}
+
+// Query reads the items of table in the partition named by key, following pagination
+func (c *DynamoDBClient) Query(ctx context.Context, table string, key Item) ([]Item, error) {
+	if len(key) != 1 {
+		return nil, fmt.Errorf("query needs exactly one partition key attribute, got %d", len(key))
+	}
+	var name string
+	var value events.DynamoDBAttributeValue
+	for name, value = range key {
+		break
+	}
+	return c.paginate(ctx, "Query", map[string]interface{}{
+		"TableName":                 table,
+		"KeyConditionExpression":    "#pk = :pk",
+		"ExpressionAttributeNames":  map[string]string{"#pk": name},
+		"ExpressionAttributeValues": Item{":pk": value},
+		"ConsistentRead":            true,
+	})
+}
+
+// paginate calls a Scan or Query operation until the last page
+func (c *DynamoDBClient) paginate(ctx context.Context, operation string, input map[string]interface{}) ([]Item, error) {
+	var items []Item
+	for {
+		var output struct {
+			Items            []Item `json:"Items"`
+			LastEvaluatedKey Item   `json:"LastEvaluatedKey"`
+		}
+		if err := c.call(ctx, operation, input, &output); err != nil {
+			return nil, err
+		}
+		items = append(items, output.Items...)
+		if len(output.LastEvaluatedKey) == 0 {
+			return items, nil
+		}
+		input["ExclusiveStartKey"] = output.LastEvaluatedKey
+	}
+}
+
+// DeleteItem deletes the item with key from table
+func (c *DynamoDBClient) DeleteItem(ctx context.Context, table string, key Item) error {
+	return c.call(ctx, "DeleteItem", map[string]interface{}{"TableName": table, "Key": key}, nil)
+}
+
+// call sends a signed request for operation and decodes the response into output
+func (c *DynamoDBClient) call(ctx context.Context, operation string, input, output interface{}) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	request.Header.Set("X-Amz-Target", "DynamoDB_20120810."+operation)
+	c.signer.Sign(request, body)
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", operation, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("%s returned %d: %s", operation, response.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if output == nil {
+		return nil
+	}
+	return json.NewDecoder(response.Body).Decode(output)
+}
+
+// MemoryDynamoDB is an in-memory stand-in for DynamoDB tables; tables are keyed by the string attribute id
+// unless DefineTable names other key attributes
+type MemoryDynamoDB struct {
+	mu     sync.Mutex
+	keys   map[string][]string
+	tables map[string]map[string]Item
+}
+
+// NewMemoryDynamoDB creates a new empty MemoryDynamoDB
+func NewMemoryDynamoDB() *MemoryDynamoDB {
+	return &MemoryDynamoDB{
+		keys:   make(map[string][]string),
+		tables: make(map[string]map[string]Item),
+	}
+}
+
+// DefineTable sets the string key attributes of table: the partition key, then an optional sort key
+func (m *MemoryDynamoDB) DefineTable(table string, keys ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keys[table] = keys
+}
+
+// PutItem stores item
+func (m *MemoryDynamoDB) PutItem(ctx context.Context, table string, item Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, err := m.itemKey(table, item)
+	if err != nil {
+		return err
+	}
+	if m.tables[table] == nil {
+		m.tables[table] = make(map[string]Item)
+	}
+	m.tables[table][key] = item
+	return nil
+}
+
+// GetItem returns the item with key, or nil
+func (m *MemoryDynamoDB) GetItem(ctx context.Context, table string, key Item) (Item, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	itemKey, err := m.itemKey(table, key)
+	if err != nil {
+		return nil, err
+	}
+	return m.tables[table][itemKey], nil
+}
+
+// Scan returns every item of table
+func (m *MemoryDynamoDB) Scan(ctx context.Context, table string) ([]Item, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	items := make([]Item, 0, len(m.tables[table]))
+	for _, item := range m.tables[table] {
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Query returns the items in the partition named by key, in sort key order
+func (m *MemoryDynamoDB) Query(ctx context.Context, table string, key Item) ([]Item, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := m.tableKeys(table)
+	partition, ok := stringAttribute(key, keys[0])
+	if !ok || len(key) != 1 {
+		return nil, fmt.Errorf("query needs the partition key %s", keys[0])
+	}
+
+	var items []Item
+	for _, item := range m.tables[table] {
+		if value, _ := stringAttribute(item, keys[0]); value == partition {
+			items = append(items, item)
+		}
+	}
+	if len(keys) > 1 {
+		sort.Slice(items, func(i, j int) bool {
+			a, _ := stringAttribute(items[i], keys[1])
+			b, _ := stringAttribute(items[j], keys[1])
+			return a < b
+		})
+	}
+	return items, nil
+}
+
+// DeleteItem removes the item with key
+func (m *MemoryDynamoDB) DeleteItem(ctx context.Context, table string, key Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	itemKey, err := m.itemKey(table, key)
+	if err != nil {
+		return err
+	}
+	delete(m.tables[table], itemKey)
+	return nil
+}
+
+// tableKeys returns the key attributes of table; m.mu must be held
+func (m *MemoryDynamoDB) tableKeys(table string) []string {
+	if keys, ok := m.keys[table]; ok {
+		return keys
+	}
+	return []string{"id"}
+}
+
+// itemKey joins the key attribute values of item; m.mu must be held
+func (m *MemoryDynamoDB) itemKey(table string, item Item) (string, error) {
+	var parts []string
+	for _, name := range m.tableKeys(table) {
+		value, ok := stringAttribute(item, name)
+		if !ok {
+			return "", fmt.Errorf("item has no %s", name)
+		}
+		parts = append(parts, value)
+	}
+	return strings.Join(parts, "\x00"), nil
+}
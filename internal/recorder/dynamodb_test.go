@@ -0,0 +1,112 @@
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"echo-api/internal/sigv4"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestDynamoDBStore(t *testing.T) {
+	exerciseStore(t, NewDynamoDBStore(NewMemoryDynamoDB(), "requests", 0))
+
+	// The TTL attribute is set from the record time
+	api := NewMemoryDynamoDB()
+	store := NewDynamoDBStore(api, "requests", time.Hour)
+	store.Put(context.Background(), testRecord("1", "GET", "/", nil, 0))
+	item, _ := api.GetItem(context.Background(), "requests", recordKey("1"))
+	if expiresAt := item["expiresAt"].Number(); expiresAt != "1704168245" {
+		t.Errorf("Expected expiresAt 1704168245, got %s", expiresAt)
+	}
+}
+
+func TestDynamoDBClient(t *testing.T) {
+	var targets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		targets = append(targets, r.Header.Get("X-Amz-Target"))
+		if !strings.Contains(r.Header.Get("Authorization"), "SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date;x-amz-target") {
+			t.Errorf("Unexpected Authorization %q", r.Header.Get("Authorization"))
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var input map[string]json.RawMessage
+		json.Unmarshal(body, &input)
+
+		// Answer the scan in two pages
+		if _, ok := input["ExclusiveStartKey"]; ok {
+			io.WriteString(w, `{"Items": [{"id": {"S": "b"}}]}`)
+			return
+		}
+		io.WriteString(w, `{"Items": [{"id": {"S": "a"}}], "LastEvaluatedKey": {"id": {"S": "a"}}}`)
+	}))
+	defer server.Close()
+
+	client := NewDynamoDBClient(sigv4.NewSigner(sigv4.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, "us-east-1", "dynamodb"))
+	client.endpoint = server.URL + "/"
+
+	items, err := client.Scan(context.Background(), "requests")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[1]["id"].String() != "b" {
+		t.Errorf("Expected both pages, got %v", items)
+	}
+	if len(targets) != 2 || targets[0] != "DynamoDB_20120810.Scan" {
+		t.Errorf("Unexpected targets %v", targets)
+	}
+}
+
+func TestDynamoDBClient_Query(t *testing.T) {
+	var inputs []map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if target := r.Header.Get("X-Amz-Target"); target != "DynamoDB_20120810.Query" {
+			t.Errorf("Unexpected target %q", target)
+		}
+		body, _ := io.ReadAll(r.Body)
+		var input map[string]json.RawMessage
+		json.Unmarshal(body, &input)
+		inputs = append(inputs, input)
+
+		if _, ok := input["ExclusiveStartKey"]; ok {
+			io.WriteString(w, `{"Items": [{"bin": {"S": "t"}, "id": {"S": "2"}}]}`)
+			return
+		}
+		io.WriteString(w, `{"Items": [{"bin": {"S": "t"}, "id": {"S": "1"}}], "LastEvaluatedKey": {"bin": {"S": "t"}, "id": {"S": "1"}}}`)
+	}))
+	defer server.Close()
+
+	client := NewDynamoDBClient(sigv4.NewSigner(sigv4.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, "us-east-1", "dynamodb"))
+	client.endpoint = server.URL + "/"
+
+	items, err := client.Query(context.Background(), "bins", Item{"bin": events.NewStringAttribute("t")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[1]["id"].String() != "2" {
+		t.Errorf("Expected both pages, got %v", items)
+	}
+	if len(inputs) != 2 || string(inputs[0]["ExpressionAttributeNames"]) != `{"#pk":"bin"}` || string(inputs[0]["ExpressionAttributeValues"]) != `{":pk":{"S":"t"}}` {
+		t.Errorf("Unexpected query input %v", inputs)
+	}
+}
+
+func TestMemoryDynamoDB_Query(t *testing.T) {
+	api := NewMemoryDynamoDB()
+	api.DefineTable("bins", "bin", "id")
+	for _, key := range [][2]string{{"a", "2"}, {"b", "1"}, {"a", "1"}} {
+		api.PutItem(context.Background(), "bins", Item{"bin": events.NewStringAttribute(key[0]), "id": events.NewStringAttribute(key[1])})
+	}
+
+	items, err := api.Query(context.Background(), "bins", Item{"bin": events.NewStringAttribute("a")})
+	if err != nil || len(items) != 2 || items[0]["id"].String() != "1" || items[1]["id"].String() != "2" {
+		t.Errorf("Expected partition a in sort key order, got %v (%v)", items, err)
+	}
+}
@@ -0,0 +1,110 @@
+package recorder
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+
+	"echo-api/internal/models"
+)
+
+// maxLineSize bounds a JSONL line; a record holds a body of up to 6MB, base64 encoded
+const maxLineSize = 16 * 1024 * 1024
+
+// FileStore appends records to a JSON Lines file
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileStore creates a new FileStore writing to path
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Put appends record as one line
+func (f *FileStore) Put(ctx context.Context, record *models.RecordedRequest) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Get returns the record with id
+func (f *FileStore) Get(ctx context.Context, id string) (*models.RecordedRequest, error) {
+	records, err := f.readAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// List returns the records matching filter, newest first
+func (f *FileStore) List(ctx context.Context, filter Filter) ([]*models.RecordedRequest, error) {
+	records, err := f.readAll()
+	if err != nil {
+		return nil, err
+	}
+	return filter.Select(records), nil
+}
+
+// Clear truncates the file
+func (f *FileStore) Clear(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	err := os.Truncate(f.path, 0)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// readAll reads every record, skipping lines that are not valid records
+func (f *FileStore) readAll() ([]*models.RecordedRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.Open(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []*models.RecordedRequest
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		record := &models.RecordedRequest{}
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil || record.ID == "" || record.Request == nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
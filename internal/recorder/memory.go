@@ -0,0 +1,64 @@
+package recorder
+
+import (
+	"context"
+	"sync"
+
+	"echo-api/internal/models"
+)
+
+// MemoryStore keeps the most recent records in memory, dropping the oldest beyond its capacity
+type MemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	records  []*models.RecordedRequest
+}
+
+// NewMemoryStore creates a new MemoryStore holding up to capacity records
+func NewMemoryStore(capacity int) *MemoryStore {
+	return &MemoryStore{capacity: capacity}
+}
+
+// Put adds record, evicting the oldest record when the store is full
+func (m *MemoryStore) Put(ctx context.Context, record *models.RecordedRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.records) == m.capacity {
+		copy(m.records, m.records[1:])
+		m.records = m.records[:len(m.records)-1]
+	}
+	m.records = append(m.records, record)
+	return nil
+}
+
+// Get returns the record with id
+func (m *MemoryStore) Get(ctx context.Context, id string) (*models.RecordedRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, record := range m.records {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// List returns the records matching filter, newest first
+func (m *MemoryStore) List(ctx context.Context, filter Filter) ([]*models.RecordedRequest, error) {
+	m.mu.Lock()
+	records := append([]*models.RecordedRequest(nil), m.records...)
+	m.mu.Unlock()
+
+	return filter.Select(records), nil
+}
+
+// Clear removes every record
+func (m *MemoryStore) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.records = nil
+	return nil
+}
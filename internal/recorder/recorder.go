@@ -0,0 +1,170 @@
+package recorder
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"echo-api/internal/models"
+	"echo-api/internal/sigv4"
+)
+
+// ErrNotFound is returned when no recorded request has the id
+var ErrNotFound = errors.New("recorded request not found")
+
+// credentialHeaders are redacted before a request is stored, since reading the history needs no authentication
+var credentialHeaders = map[string]bool{
+	"authorization":        true,
+	"proxy-authorization":  true,
+	"cookie":               true,
+	"x-api-key":            true,
+	"x-amz-security-token": true,
+}
+
+// redacted replaces the value of a credential header
+const redacted = "[REDACTED]"
+
+// Store persists recorded requests
+type Store interface {
+	Put(ctx context.Context, record *models.RecordedRequest) error
+	Get(ctx context.Context, id string) (*models.RecordedRequest, error)
+	// List returns the records matching filter, newest first
+	List(ctx context.Context, filter Filter) ([]*models.RecordedRequest, error)
+	Clear(ctx context.Context) error
+}
+
+// Filter selects recorded requests; zero fields match everything
+type Filter struct {
+	// Path matches exactly, or as a prefix when it ends with *
+	Path   string
+	Method string
+	// Header requires the header to be present, or to have a value when written as name:value
+	Header string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+}
+
+// Match reports whether record passes the filter
+func (f Filter) Match(record *models.RecordedRequest) bool {
+	request := record.Request
+	if f.Path != "" {
+		if prefix, ok := strings.CutSuffix(f.Path, "*"); ok {
+			if !strings.HasPrefix(request.Path, prefix) {
+				return false
+			}
+		} else if request.Path != f.Path {
+			return false
+		}
+	}
+	if f.Method != "" && !strings.EqualFold(request.Method, f.Method) {
+		return false
+	}
+	if f.Header != "" {
+		name, value, hasValue := strings.Cut(f.Header, ":")
+		actual, present := models.HeaderValue(request.Headers, strings.TrimSpace(name))
+		if !present || (hasValue && actual != strings.TrimSpace(value)) {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && record.RecordedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && record.RecordedAt.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Select sorts records newest first and keeps those matching the filter, up to its limit
+func (f Filter) Select(records []*models.RecordedRequest) []*models.RecordedRequest {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].RecordedAt.After(records[j].RecordedAt)
+	})
+
+	selected := make([]*models.RecordedRequest, 0, len(records))
+	for _, record := range records {
+		if f.Limit > 0 && len(selected) == f.Limit {
+			break
+		}
+		if f.Match(record) {
+			selected = append(selected, record)
+		}
+	}
+	return selected
+}
+
+// NewRecord wraps a copy of request in a record with a new id, redacting credential headers
+func NewRecord(request *models.EchoRequest, recordedAt time.Time) *models.RecordedRequest {
+	stored := *request
+	stored.Headers = make(map[string]string, len(request.Headers))
+	for name, value := range request.Headers {
+		if credentialHeaders[strings.ToLower(name)] {
+			value = redacted
+		}
+		stored.Headers[name] = value
+	}
+
+	return &models.RecordedRequest{
+		ID:         NewID(recordedAt),
+		RecordedAt: recordedAt.UTC(),
+		Request:    &stored,
+	}
+}
+
+// NewID returns an id that sorts by time: the timestamp in hex followed by random bytes
+func NewID(at time.Time) string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%016x%s", at.UnixNano(), hex.EncodeToString(suffix))
+}
+
+// StoreFromEnv creates the store selected by RECORDER_STORE (memory, file or dynamodb);
+// it returns nil when recording is disabled
+func StoreFromEnv() (Store, error) {
+	switch kind := os.Getenv("RECORDER_STORE"); kind {
+	case "":
+		return nil, nil
+	case "memory":
+		capacity := 1000
+		if value := os.Getenv("RECORDER_CAPACITY"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid RECORDER_CAPACITY %q", value)
+			}
+			capacity = parsed
+		}
+		return NewMemoryStore(capacity), nil
+	case "file":
+		path := os.Getenv("RECORDER_FILE")
+		if path == "" {
+			// /tmp is the only writable directory in Lambda
+			path = "/tmp/requests.jsonl"
+		}
+		return NewFileStore(path), nil
+	case "dynamodb":
+		table := os.Getenv("RECORDER_TABLE")
+		if table == "" {
+			return nil, errors.New("RECORDER_TABLE is required for the dynamodb store")
+		}
+		var ttl time.Duration
+		if value := os.Getenv("RECORDER_TTL"); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RECORDER_TTL: %w", err)
+			}
+			ttl = parsed
+		}
+		signer := sigv4.NewSigner(sigv4.AWSCredentialsFromEnv(), os.Getenv("AWS_REGION"), "dynamodb")
+		return NewDynamoDBStore(NewDynamoDBClient(signer), table, ttl), nil
+	default:
+		return nil, fmt.Errorf("unknown RECORDER_STORE %q", kind)
+	}
+}
@@ -0,0 +1,160 @@
+package recorder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"echo-api/internal/models"
+)
+
+var baseTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// testRecord builds a record received offset after baseTime
+func testRecord(id, method, path string, headers map[string]string, offset time.Duration) *models.RecordedRequest {
+	return &models.RecordedRequest{
+		ID:         id,
+		RecordedAt: baseTime.Add(offset),
+		Request:    models.NewEchoRequest(method, path, headers, map[string]string{}, ""),
+	}
+}
+
+// testRecords returns three records, oldest first
+func testRecords() []*models.RecordedRequest {
+	return []*models.RecordedRequest{
+		testRecord("1", "POST", "/hooks/github", map[string]string{"X-GitHub-Event": "push"}, 0),
+		testRecord("2", "GET", "/status", nil, time.Minute),
+		testRecord("3", "POST", "/hooks/stripe", map[string]string{"Stripe-Signature": "t=1"}, 2*time.Minute),
+	}
+}
+
+func TestFilter_Select(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{"everything newest first", Filter{}, []string{"3", "2", "1"}},
+		{"path prefix", Filter{Path: "/hooks/*"}, []string{"3", "1"}},
+		{"exact path", Filter{Path: "/hooks"}, []string{}},
+		{"method", Filter{Method: "get"}, []string{"2"}},
+		{"header present", Filter{Header: "x-github-event"}, []string{"1"}},
+		{"header value", Filter{Header: "X-GitHub-Event: pull_request"}, []string{}},
+		{"time range", Filter{Since: baseTime.Add(30 * time.Second), Until: baseTime.Add(90 * time.Second)}, []string{"2"}},
+		{"limit", Filter{Limit: 2}, []string{"3", "2"}},
+	}
+
+	for _, tc := range testCases {
+		selected := tc.filter.Select(testRecords())
+		ids := make([]string, 0, len(selected))
+		for _, record := range selected {
+			ids = append(ids, record.ID)
+		}
+		if len(ids) != len(tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, ids)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tc.expected[i] {
+				t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, ids)
+				break
+			}
+		}
+	}
+}
+
+// exerciseStore runs the Store contract against store
+func exerciseStore(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	for _, record := range testRecords() {
+		if err := store.Put(ctx, record); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	record, err := store.Get(ctx, "2")
+	if err != nil || record.Request.Path != "/status" || !record.RecordedAt.Equal(baseTime.Add(time.Minute)) {
+		t.Errorf("Expected record 2, got %+v (%v)", record, err)
+	}
+	if _, err := store.Get(ctx, "missing"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	records, err := store.List(ctx, Filter{Method: "POST"})
+	if err != nil || len(records) != 2 || records[0].ID != "3" {
+		t.Errorf("Expected the POST records newest first, got %v (%v)", records, err)
+	}
+
+	if err := store.Clear(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if records, _ := store.List(ctx, Filter{}); len(records) != 0 {
+		t.Errorf("Expected an empty store, got %d records", len(records))
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	exerciseStore(t, NewMemoryStore(10))
+
+	// The oldest record is evicted when the store is full
+	store := NewMemoryStore(2)
+	for _, record := range testRecords() {
+		store.Put(context.Background(), record)
+	}
+	if _, err := store.Get(context.Background(), "1"); err != ErrNotFound {
+		t.Errorf("Expected record 1 to be evicted, got %v", err)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.jsonl")
+	exerciseStore(t, NewFileStore(path))
+
+	// Lines that are not records, such as hand-collected dumps, are skipped
+	os.WriteFile(path, []byte("{\"request_id\": \"user-001\"}\nnot json\n"), 0o644)
+	store := NewFileStore(path)
+	store.Put(context.Background(), testRecord("4", "GET", "/", nil, 0))
+	if records, err := store.List(context.Background(), Filter{}); err != nil || len(records) != 1 {
+		t.Errorf("Expected one record, got %v (%v)", records, err)
+	}
+}
+
+func TestNewRecord_RedactsCredentials(t *testing.T) {
+	request := models.NewEchoRequest("POST", "/hooks", map[string]string{
+		"Authorization":  "Bearer secret",
+		"cookie":         "session=abc",
+		"X-GitHub-Event": "push",
+	}, map[string]string{}, "")
+
+	record := NewRecord(request, baseTime)
+	headers := record.Request.Headers
+	if headers["Authorization"] != redacted || headers["cookie"] != redacted || headers["X-GitHub-Event"] != "push" {
+		t.Errorf("Expected credentials to be redacted, got %v", headers)
+	}
+	// The echo still shows the request as received
+	if request.Headers["Authorization"] != "Bearer secret" {
+		t.Errorf("Expected the request to be left unchanged, got %v", request.Headers)
+	}
+}
+
+func TestStoreFromEnv(t *testing.T) {
+	t.Setenv("RECORDER_STORE", "")
+	if store, err := StoreFromEnv(); store != nil || err != nil {
+		t.Errorf("Expected recording to be disabled, got %v (%v)", store, err)
+	}
+
+	t.Setenv("RECORDER_STORE", "dynamodb")
+	t.Setenv("RECORDER_TABLE", "")
+	if _, err := StoreFromEnv(); err == nil {
+		t.Error("Expected an error without RECORDER_TABLE")
+	}
+
+	t.Setenv("RECORDER_STORE", "redis")
+	if _, err := StoreFromEnv(); err == nil {
+		t.Error("Expected an error for an unknown store")
+	}
+}
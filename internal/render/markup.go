@@ -0,0 +1,163 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"strings"
+	"unicode"
+
+	"echo-api/internal/models"
+)
+
+// encodeXML renders the JSON structure of the response as XML elements
+func encodeXML(response *models.EchoResponse) ([]byte, error) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+
+	// Walking the JSON tokens keeps the field order of the JSON output
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := writeXMLValue(&buf, decoder, "echoResponse", 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeXMLValue writes the next JSON value from the decoder as an element called name
+func writeXMLValue(buf *bytes.Buffer, decoder *json.Decoder, name string, depth int) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	indent := strings.Repeat("  ", depth)
+	start, end := xmlTags(name)
+
+	delim, isDelim := token.(json.Delim)
+	if !isDelim {
+		if token == nil {
+			buf.WriteString(indent + strings.TrimSuffix(start, ">") + "/>\n")
+			return nil
+		}
+		buf.WriteString(indent + start)
+		if err := xml.EscapeText(buf, []byte(fmt.Sprint(token))); err != nil {
+			return err
+		}
+		buf.WriteString(end + "\n")
+		return nil
+	}
+
+	if !decoder.More() {
+		buf.WriteString(indent + strings.TrimSuffix(start, ">") + "/>\n")
+		_, err := decoder.Token()
+		return err
+	}
+
+	buf.WriteString(indent + start + "\n")
+	for decoder.More() {
+		childName := "item"
+		if delim == '{' {
+			key, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			childName = key.(string)
+		}
+		if err := writeXMLValue(buf, decoder, childName, depth+1); err != nil {
+			return err
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	buf.WriteString(indent + end + "\n")
+	return nil
+}
+
+// xmlTags returns the start and end tags for name, falling back to <entry key="..."> for invalid element names
+func xmlTags(name string) (string, string) {
+	if isXMLName(name) {
+		return "<" + name + ">", "</" + name + ">"
+	}
+	var key bytes.Buffer
+	xml.EscapeText(&key, []byte(name))
+	return `<entry key="` + key.String() + `">`, "</entry>"
+}
+
+// isXMLName reports whether name can be used as an element name as is
+func isXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		if unicode.IsLetter(r) || r == '_' {
+			continue
+		}
+		if i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// pageTemplate renders the echo as a readable HTML page
+var pageTemplate = template.Must(template.New("echo").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Echo: {{.Request.Method}} {{.Request.Path}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #ddd; padding: 0.3rem 0.6rem; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; }
+pre { background: #f5f5f5; padding: 1rem; overflow-x: auto; }
+</style>
+</head>
+<body>
+<h1><code>{{.Request.Method}} {{.Request.Path}}</code></h1>
+<p>{{.Message}} at {{.ProcessedAt}}</p>
+<h2>Headers</h2>
+<table>
+<tr><th>Name</th><th>Value</th></tr>
+{{range $name, $value := .Request.Headers}}<tr><td>{{$name}}</td><td>{{$value}}</td></tr>
+{{end}}</table>
+<h2>Query parameters</h2>
+<table>
+<tr><th>Name</th><th>Value</th></tr>
+{{range $name, $value := .Request.QueryParams}}<tr><td>{{$name}}</td><td>{{$value}}</td></tr>
+{{end}}</table>
+{{if .Request.Body}}<h2>Body</h2>
+<pre>{{.Request.Body}}</pre>
+{{end}}<h2>Full response</h2>
+<pre>{{.JSON}}</pre>
+</body>
+</html>
+`))
+
+// encodeHTML renders the response as an HTML page
+func encodeHTML(response *models.EchoResponse) ([]byte, error) {
+	pretty, err := encodePrettyJSON(response)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = pageTemplate.Execute(&buf, struct {
+		*models.EchoResponse
+		JSON string
+	}{response, string(pretty)})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
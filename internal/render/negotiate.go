@@ -0,0 +1,105 @@
+package render
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mediaRange is a single entry of an Accept header
+type mediaRange struct {
+	mediaType string
+	subtype   string
+	quality   float64
+}
+
+// specificity ranks exact types above type/* and */*
+func (r mediaRange) specificity() int {
+	switch {
+	case r.mediaType == "*":
+		return 0
+	case r.subtype == "*":
+		return 1
+	}
+	return 2
+}
+
+// matches reports whether the range covers contentType
+func (r mediaRange) matches(contentType string) bool {
+	mediaType, subtype, _ := strings.Cut(contentType, "/")
+	return (r.mediaType == "*" || r.mediaType == mediaType) && (r.subtype == "*" || r.subtype == subtype)
+}
+
+// Negotiate picks the format for a request from the ?format= override and the Accept header
+func Negotiate(accept, override string) (*Format, bool) {
+	if override != "" {
+		format := Lookup(override)
+		return format, format != nil
+	}
+
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		return formats[0], true
+	}
+
+	var best *Format
+	bestQuality := 0.0
+	for _, format := range formats {
+		quality := format.quality(ranges)
+		// Ties keep the earlier format, so server preference order breaks them
+		if quality > bestQuality {
+			best = format
+			bestQuality = quality
+		}
+	}
+	return best, best != nil
+}
+
+// quality returns the q-value the most specific matching range gives to any of the format's media types;
+// wildcards only match the primary media type, so text/* picks text/plain rather than text/yaml
+func (f *Format) quality(ranges []mediaRange) float64 {
+	best := 0.0
+	for i, contentType := range f.MediaTypes {
+		for _, r := range ranges {
+			if i > 0 && r.specificity() < 2 {
+				continue
+			}
+			if r.matches(contentType) {
+				// ranges are sorted most specific first, so the first match decides
+				if r.quality > best {
+					best = r.quality
+				}
+				break
+			}
+		}
+	}
+	return best
+}
+
+// parseAccept parses an Accept header, most specific ranges first
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, item := range strings.Split(accept, ",") {
+		params := strings.Split(item, ";")
+		mediaType, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(params[0])), "/")
+		if !ok || mediaType == "" || subtype == "" {
+			continue
+		}
+
+		r := mediaRange{mediaType: mediaType, subtype: subtype, quality: 1}
+		for _, param := range params[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil && q >= 0 && q <= 1 {
+					r.quality = q
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].specificity() > ranges[j].specificity()
+	})
+	return ranges
+}
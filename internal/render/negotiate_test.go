@@ -0,0 +1,51 @@
+package render
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	testCases := []struct {
+		accept   string
+		override string
+		expected string
+	}{
+		{"", "", "json"},
+		{"*/*", "", "json"},
+		{"application/json", "", "json"},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", "", "html"},
+		{"application/xml;q=0.5, application/yaml", "", "yaml"},
+		{"text/*", "", "text"},
+		{"text/*;q=0.5, text/html", "", "html"},
+		{"*/*;q=0.1, application/json;q=0", "", "yaml"},
+		{"TEXT/PLAIN", "", "text"},
+		{"text/html", "pretty", "pretty"},
+		{"", "YML", "yaml"},
+	}
+
+	for _, tc := range testCases {
+		format, ok := Negotiate(tc.accept, tc.override)
+		if !ok {
+			t.Errorf("For Accept %q override %q, expected %s, got no match", tc.accept, tc.override, tc.expected)
+			continue
+		}
+		if format.Name != tc.expected {
+			t.Errorf("For Accept %q override %q, expected %s, got %s", tc.accept, tc.override, tc.expected, format.Name)
+		}
+	}
+}
+
+func TestNegotiate_NotAcceptable(t *testing.T) {
+	testCases := []struct {
+		accept   string
+		override string
+	}{
+		{"image/png", ""},
+		{"application/json;q=0", ""},
+		{"", "csv"},
+	}
+
+	for _, tc := range testCases {
+		if format, ok := Negotiate(tc.accept, tc.override); ok {
+			t.Errorf("For Accept %q override %q, expected no match, got %s", tc.accept, tc.override, format.Name)
+		}
+	}
+}
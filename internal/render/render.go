@@ -0,0 +1,217 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"echo-api/internal/codec"
+	"echo-api/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format describes one way of rendering an echo response
+type Format struct {
+	Name        string
+	ContentType string
+	MediaTypes  []string
+	Binary      bool
+	encode      func(*models.EchoResponse) ([]byte, error)
+}
+
+// formats lists the supported formats in server preference order
+var formats = []*Format{
+	{
+		Name:        "json",
+		ContentType: "application/json",
+		MediaTypes:  []string{"application/json"},
+		encode:      encodeJSON,
+	},
+	{
+		// Pretty JSON is only selected through ?format=pretty
+		Name:        "pretty",
+		ContentType: "application/json",
+		encode:      encodePrettyJSON,
+	},
+	{
+		Name:        "yaml",
+		ContentType: "application/yaml",
+		MediaTypes:  []string{"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml"},
+		encode:      encodeYAML,
+	},
+	{
+		Name:        "xml",
+		ContentType: "application/xml",
+		MediaTypes:  []string{"application/xml", "text/xml"},
+		encode:      encodeXML,
+	},
+	{
+		Name:        "text",
+		ContentType: "text/plain; charset=utf-8",
+		MediaTypes:  []string{"text/plain"},
+		encode:      encodeText,
+	},
+	{
+		Name:        "html",
+		ContentType: "text/html; charset=utf-8",
+		MediaTypes:  []string{"text/html", "application/xhtml+xml"},
+		encode:      encodeHTML,
+	},
+	{
+		Name:        "msgpack",
+		ContentType: "application/msgpack",
+		MediaTypes:  []string{"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"},
+		Binary:      true,
+		encode:      encodeMsgPack,
+	},
+	{
+		Name:        "cbor",
+		ContentType: "application/cbor",
+		MediaTypes:  []string{"application/cbor"},
+		Binary:      true,
+		encode:      encodeCBOR,
+	},
+	{
+		Name:        "protobuf",
+		ContentType: "application/x-protobuf",
+		MediaTypes:  []string{"application/x-protobuf", "application/protobuf", "application/vnd.google.protobuf"},
+		Binary:      true,
+		encode:      codec.MarshalProto,
+	},
+}
+
+// Default returns the format used when the client expresses no preference
+func Default() *Format {
+	return formats[0]
+}
+
+// Lookup returns the format with the given name, or nil
+func Lookup(name string) *Format {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "yml":
+		name = "yaml"
+	case "proto":
+		name = "protobuf"
+	}
+	for _, format := range formats {
+		if format.Name == name {
+			return format
+		}
+	}
+	return nil
+}
+
+// Supported lists the format names and media types for error messages
+func Supported() string {
+	var names []string
+	for _, format := range formats {
+		names = append(names, format.Name)
+		names = append(names, format.MediaTypes...)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Render encodes the response in the format; binary formats return raw bytes in the string
+func (f *Format) Render(response *models.EchoResponse) (string, error) {
+	data, err := f.encode(response)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// encodeJSON renders compact JSON, matching EchoResponse.ToJSON
+func encodeJSON(response *models.EchoResponse) ([]byte, error) {
+	return json.Marshal(response)
+}
+
+// encodePrettyJSON renders indented JSON
+func encodePrettyJSON(response *models.EchoResponse) ([]byte, error) {
+	return json.MarshalIndent(response, "", "  ")
+}
+
+// encodeMsgPack renders MessagePack with the same keys as the JSON output
+func encodeMsgPack(response *models.EchoResponse) ([]byte, error) {
+	return codec.MarshalMsgPack(response)
+}
+
+// encodeCBOR renders CBOR with the same keys as the JSON output
+func encodeCBOR(response *models.EchoResponse) ([]byte, error) {
+	return codec.MarshalCBOR(response)
+}
+
+// encodeYAML renders YAML with the same keys and field order as the JSON output
+func encodeYAML(response *models.EchoResponse) ([]byte, error) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML, so decoding it into a node keeps the field order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	resetStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resetStyle switches JSON flow style nodes to block style
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// encodeText renders the echoed request as raw HTTP request text
+func encodeText(response *models.EchoResponse) ([]byte, error) {
+	request := response.Request
+
+	var buf bytes.Buffer
+	target := request.Path
+	if query := encodeQuery(request.QueryParams); query != "" {
+		target += "?" + query
+	}
+	fmt.Fprintf(&buf, "%s %s HTTP/1.1\r\n", request.Method, target)
+	for _, name := range sortedKeys(request.Headers) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, request.Headers[name])
+	}
+	buf.WriteString("\r\n")
+	buf.WriteString(request.Body)
+	return buf.Bytes(), nil
+}
+
+// encodeQuery renders query parameters in a stable order
+func encodeQuery(params map[string]string) string {
+	values := url.Values{}
+	for key, value := range params {
+		values.Set(key, value)
+	}
+	return values.Encode()
+}
+
+// sortedKeys returns the keys of a string map in sorted order
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,149 @@
+package render
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"echo-api/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// sampleResponse returns a fixed echo response for rendering tests
+func sampleResponse() *models.EchoResponse {
+	return &models.EchoResponse{
+		Request: models.EchoRequest{
+			Method:      "POST",
+			Path:        "/api/echo",
+			Headers:     map[string]string{"Content-Type": "application/json", "X-Trace": "<a&b>"},
+			QueryParams: map[string]string{"q": "a b", "1st": "true"},
+			Body:        `{"key": "value"}`,
+			Timestamp:   "2023-01-01T00:00:00Z",
+		},
+		Message:     "Request successfully echoed",
+		ProcessedAt: "2023-01-01T00:00:01Z",
+	}
+}
+
+func TestRender_JSON(t *testing.T) {
+	response := sampleResponse()
+	expected, _ := response.ToJSON()
+
+	body, err := Lookup("json").Render(response)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if body != expected {
+		t.Errorf("Expected compact JSON to match ToJSON, got %s", body)
+	}
+
+	pretty, err := Lookup("pretty").Render(response)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(pretty, "\n  \"request\": {") {
+		t.Errorf("Expected indented JSON, got %s", pretty)
+	}
+}
+
+func TestRender_YAML(t *testing.T) {
+	body, err := Lookup("yaml").Render(sampleResponse())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(body, "request:\n  method: POST\n") {
+		t.Errorf("Expected block style YAML in JSON field order, got %s", body)
+	}
+
+	var parsed models.EchoResponse
+	if err := yaml.Unmarshal([]byte(body), &parsed); err != nil {
+		t.Fatalf("Failed to parse YAML: %v", err)
+	}
+	var raw map[string]map[string]map[string]interface{}
+	yaml.Unmarshal([]byte(body), &raw)
+	if raw["request"]["queryParams"]["1st"] != "true" {
+		t.Errorf("Expected string values to stay strings, got %#v", raw["request"]["queryParams"]["1st"])
+	}
+}
+
+func TestRender_XML(t *testing.T) {
+	body, err := Lookup("xml").Render(sampleResponse())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var parsed struct {
+		XMLName xml.Name `xml:"echoResponse"`
+		Request struct {
+			Method  string `xml:"method"`
+			Headers struct {
+				ContentType string `xml:"Content-Type"`
+				Trace       string `xml:"X-Trace"`
+			} `xml:"headers"`
+			Query struct {
+				Entries []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"entry"`
+			} `xml:"queryParams"`
+		} `xml:"request"`
+	}
+	if err := xml.Unmarshal([]byte(body), &parsed); err != nil {
+		t.Fatalf("Failed to parse XML: %v\n%s", err, body)
+	}
+	if parsed.Request.Method != "POST" {
+		t.Errorf("Expected method POST, got %s", parsed.Request.Method)
+	}
+	if parsed.Request.Headers.Trace != "<a&b>" {
+		t.Errorf("Expected escaped header value to round-trip, got %s", parsed.Request.Headers.Trace)
+	}
+	if len(parsed.Request.Query.Entries) != 1 || parsed.Request.Query.Entries[0].Key != "1st" {
+		t.Errorf("Expected invalid element name to use entry key, got %+v", parsed.Request.Query.Entries)
+	}
+}
+
+func TestRender_Text(t *testing.T) {
+	body, err := Lookup("text").Render(sampleResponse())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "POST /api/echo?1st=true&q=a+b HTTP/1.1\r\n" +
+		"Content-Type: application/json\r\n" +
+		"X-Trace: <a&b>\r\n" +
+		"\r\n" +
+		`{"key": "value"}`
+	if body != expected {
+		t.Errorf("Expected raw request text %q, got %q", expected, body)
+	}
+}
+
+func TestRender_HTML(t *testing.T) {
+	body, err := Lookup("html").Render(sampleResponse())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(body, "<code>POST /api/echo</code>") {
+		t.Error("Expected request line heading")
+	}
+	if strings.Contains(body, "<a&b>") || !strings.Contains(body, "&lt;a&amp;b&gt;") {
+		t.Error("Expected header values to be HTML escaped")
+	}
+}
+
+func TestRender_Binary(t *testing.T) {
+	for _, name := range []string{"msgpack", "cbor", "protobuf"} {
+		format := Lookup(name)
+		if format == nil || !format.Binary {
+			t.Fatalf("Expected binary format %s", name)
+		}
+		body, err := format.Render(sampleResponse())
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", name, err)
+		}
+		if len(body) == 0 {
+			t.Errorf("Expected %s output", name)
+		}
+	}
+}
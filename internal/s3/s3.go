@@ -0,0 +1,139 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"echo-api/internal/sigv4"
+)
+
+// ErrNotFound is returned when the object does not exist, or no longer exists
+var ErrNotFound = errors.New("object not found")
+
+// ObjectMetadata is the metadata HeadObject returns for an object
+type ObjectMetadata struct {
+	ContentType   string            `json:"contentType,omitempty"`
+	ContentLength int64             `json:"contentLength"`
+	ETag          string            `json:"eTag,omitempty"`
+	LastModified  string            `json:"lastModified,omitempty"`
+	StorageClass  string            `json:"storageClass,omitempty"`
+	VersionID     string            `json:"versionId,omitempty"`
+	Encryption    string            `json:"serverSideEncryption,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// HeadObjecter reads object metadata
+type HeadObjecter interface {
+	HeadObject(ctx context.Context, bucket, key, versionID string) (*ObjectMetadata, error)
+}
+
+// Client calls the S3 REST API with SigV4 signed requests
+type Client struct {
+	http   *http.Client
+	signer *sigv4.Signer
+}
+
+// NewClient creates a new Client that signs requests with signer
+func NewClient(signer *sigv4.Signer) *Client {
+	return &Client{
+		http:   &http.Client{Timeout: 10 * time.Second},
+		signer: signer,
+	}
+}
+
+// HeadObject sends a HEAD request for the object to the bucket's virtual-hosted endpoint
+func (c *Client) HeadObject(ctx context.Context, bucket, key, versionID string) (*ObjectMetadata, error) {
+	target := &url.URL{
+		Scheme: "https",
+		Host:   bucket + ".s3." + c.signer.Region() + ".amazonaws.com",
+		Path:   "/" + key,
+	}
+	if versionID != "" {
+		target.RawQuery = "versionId=" + url.QueryEscape(versionID)
+	}
+	return c.head(ctx, target)
+}
+
+// head sends a signed HEAD request to target and reads the metadata headers
+func (c *Client) head(ctx context.Context, target *url.URL) (*ObjectMetadata, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	c.signer.Sign(request, nil)
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("head object failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case response.StatusCode >= 300:
+		return nil, fmt.Errorf("head object returned %d", response.StatusCode)
+	}
+	return metadataFromHeader(response.Header), nil
+}
+
+// metadataFromHeader reads object metadata from HeadObject response headers
+func metadataFromHeader(header http.Header) *ObjectMetadata {
+	length, _ := strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	metadata := &ObjectMetadata{
+		ContentType:   header.Get("Content-Type"),
+		ContentLength: length,
+		ETag:          header.Get("ETag"),
+		LastModified:  header.Get("Last-Modified"),
+		StorageClass:  header.Get("X-Amz-Storage-Class"),
+		VersionID:     header.Get("X-Amz-Version-Id"),
+		Encryption:    header.Get("X-Amz-Server-Side-Encryption"),
+	}
+	for name := range header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-amz-meta-") {
+			if metadata.Metadata == nil {
+				metadata.Metadata = make(map[string]string)
+			}
+			metadata.Metadata[strings.TrimPrefix(lower, "x-amz-meta-")] = header.Get(name)
+		}
+	}
+	return metadata
+}
+
+// MemoryStore is an in-memory stand-in for S3 object metadata
+type MemoryStore struct {
+	mu      sync.Mutex
+	objects map[string]*ObjectMetadata
+}
+
+// NewMemoryStore creates a new MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{objects: make(map[string]*ObjectMetadata)}
+}
+
+// Put stores metadata for the object
+func (m *MemoryStore) Put(bucket, key string, metadata *ObjectMetadata) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[bucket+"/"+key] = metadata
+}
+
+// HeadObject returns the stored metadata, or ErrNotFound
+func (m *MemoryStore) HeadObject(ctx context.Context, bucket, key, versionID string) (*ObjectMetadata, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	metadata, ok := m.objects[bucket+"/"+key]
+	if !ok || (versionID != "" && metadata.VersionID != versionID) {
+		return nil, ErrNotFound
+	}
+	copied := *metadata
+	return &copied, nil
+}
@@ -0,0 +1,60 @@
+package s3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"echo-api/internal/sigv4"
+)
+
+func TestClient_Head(t *testing.T) {
+	var gotMethod, gotPath, gotAuthorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.EscapedPath()
+		gotAuthorization = r.Header.Get("Authorization")
+		if strings.HasSuffix(r.URL.Path, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Content-Length", "42")
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("X-Amz-Meta-Owner", "team-a")
+	}))
+	defer server.Close()
+
+	client := NewClient(sigv4.NewSigner(sigv4.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, "us-east-1", "s3"))
+	base, _ := url.Parse(server.URL)
+
+	metadata, err := client.head(context.Background(), &url.URL{Scheme: base.Scheme, Host: base.Host, Path: "/photos/a b.png"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gotMethod != "HEAD" || gotPath != "/photos/a%20b.png" || !strings.HasPrefix(gotAuthorization, "AWS4-HMAC-SHA256 ") {
+		t.Errorf("Unexpected request %s %s %q", gotMethod, gotPath, gotAuthorization)
+	}
+	if metadata.ContentType != "image/png" || metadata.ContentLength != 42 || metadata.Metadata["owner"] != "team-a" {
+		t.Errorf("Unexpected metadata %+v", metadata)
+	}
+
+	if _, err := client.head(context.Background(), &url.URL{Scheme: base.Scheme, Host: base.Host, Path: "/missing"}); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	store.Put("bucket", "a.txt", &ObjectMetadata{ContentLength: 3, VersionID: "v1"})
+
+	if metadata, err := store.HeadObject(context.Background(), "bucket", "a.txt", ""); err != nil || metadata.ContentLength != 3 {
+		t.Errorf("Expected stored metadata, got %+v %v", metadata, err)
+	}
+	if _, err := store.HeadObject(context.Background(), "bucket", "a.txt", "v2"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for another version, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"echo-api/internal/handler"
+	"echo-api/internal/stream"
+	"echo-api/pkg/logger"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/gorilla/websocket"
+)
+
+// maxBodySize caps the request body the server reads, matching the Lambda payload limit
+const maxBodySize = 6 * 1024 * 1024
+
+// Server serves the echo handlers over plain HTTP for local development and tests
+type Server struct {
+	logger      *logger.Logger
+	handler     *handler.LambdaHandler
+	websocket   *handler.WebSocketHandler
+	connections *connections
+	upgrader    websocket.Upgrader
+}
+
+// New creates a new Server around h; WebSocket upgrades on any path are echoed through the WebSocket handler
+func New(h *handler.LambdaHandler) *Server {
+	conns := newConnections()
+	return &Server{
+		logger:      logger.New(),
+		handler:     h,
+		websocket:   handler.NewWebSocketHandlerWithPoster(conns),
+		connections: conns,
+		upgrader: websocket.Upgrader{
+			// Echo clients connect from any origin
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// ServeHTTP converts the request into an API Gateway proxy request and writes the handler's response
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		s.serveWebSocket(w, r)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxBodySize {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	request := proxyRequest(r, body)
+
+	if response, ok := s.handler.Stream(r.Context(), &request); ok {
+		s.writeStream(r.Context(), w, response)
+		return
+	}
+
+	response, err := s.handler.HandleRequest(r.Context(), request)
+	if err != nil {
+		s.logger.Error("Handler failed", map[string]interface{}{
+			"error": err.Error(),
+			"path":  r.URL.Path,
+		})
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, response)
+}
+
+// writeStream sends the status and headers, then flushes every chunk the stream writes
+func (s *Server) writeStream(ctx context.Context, w http.ResponseWriter, response *stream.Response) {
+	for name, value := range response.Headers {
+		w.Header().Set(name, value)
+	}
+	w.WriteHeader(response.StatusCode)
+
+	if err := response.Body(ctx, flushWriter{w}); err != nil && ctx.Err() == nil {
+		s.logger.Warn("Stream ended with an error", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// flushWriter flushes after every write so streamed chunks reach the client immediately
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+// Write writes p and flushes it
+func (f flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
+
+// writeResponse writes a buffered proxy response, decoding base64 bodies
+func writeResponse(w http.ResponseWriter, response events.APIGatewayProxyResponse) {
+	for name, value := range response.Headers {
+		w.Header().Set(name, value)
+	}
+	for name, values := range response.MultiValueHeaders {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+
+	body := []byte(response.Body)
+	if response.IsBase64Encoded {
+		if decoded, err := base64.StdEncoding.DecodeString(response.Body); err == nil {
+			body = decoded
+		}
+	}
+
+	w.WriteHeader(response.StatusCode)
+	w.Write(body)
+}
+
+// proxyRequest builds the API Gateway proxy request API Gateway would send for r; the body is
+// always passed base64 encoded, as for binary media types
+func proxyRequest(r *http.Request, body []byte) events.APIGatewayProxyRequest {
+	headers := make(map[string]string, len(r.Header)+1)
+	for name, values := range r.Header {
+		headers[name] = strings.Join(values, ",")
+	}
+	headers["Host"] = r.Host
+
+	query := make(map[string]string)
+	for name, values := range r.URL.Query() {
+		query[name] = values[0]
+	}
+
+	sourceIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		sourceIP = r.RemoteAddr
+	}
+
+	return events.APIGatewayProxyRequest{
+		HTTPMethod:            r.Method,
+		Path:                  r.URL.Path,
+		Headers:               headers,
+		QueryStringParameters: query,
+		Body:                  base64.StdEncoding.EncodeToString(body),
+		IsBase64Encoded:       true,
+		RequestContext: events.APIGatewayProxyRequestContext{
+			HTTPMethod: r.Method,
+			Path:       r.URL.Path,
+			DomainName: r.Host,
+			Identity: events.APIGatewayRequestIdentity{
+				SourceIP:  sourceIP,
+				UserAgent: r.UserAgent(),
+			},
+		},
+	}
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"echo-api/internal/handler"
+	"echo-api/internal/models"
+)
+
+func TestServer_Echo(t *testing.T) {
+	server := httptest.NewServer(New(handler.NewLambdaHandler()))
+	defer server.Close()
+
+	response, err := http.Post(server.URL+"/hello?name=world", "application/json", strings.NewReader(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", response.StatusCode)
+	}
+	var echoResponse models.EchoResponse
+	if err := json.NewDecoder(response.Body).Decode(&echoResponse); err != nil {
+		t.Fatalf("Expected JSON echo: %v", err)
+	}
+	if echoResponse.Request.Path != "/hello" || echoResponse.Request.QueryParams["name"] != "world" {
+		t.Errorf("Unexpected request %+v", echoResponse.Request)
+	}
+	if echoResponse.Request.Body != `{"a":1}` || echoResponse.Request.IsBase64Encoded {
+		t.Errorf("Expected text body, got %q (base64 %v)", echoResponse.Request.Body, echoResponse.Request.IsBase64Encoded)
+	}
+}
+
+func TestServer_Range(t *testing.T) {
+	server := httptest.NewServer(New(handler.NewLambdaHandler()))
+	defer server.Close()
+
+	request, _ := http.NewRequest("GET", server.URL+"/range/26", nil)
+	request.Header.Set("Range", "bytes=23-")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(response.Body)
+	if response.StatusCode != http.StatusPartialContent || string(body) != "xyz" {
+		t.Errorf("Expected 206 with xyz, got %d %q", response.StatusCode, body)
+	}
+}
+
+func TestServer_SSE(t *testing.T) {
+	server := httptest.NewServer(New(handler.NewLambdaHandler()))
+	defer server.Close()
+
+	request, _ := http.NewRequest("GET", server.URL+"/sse/4?interval=0&event=tick", nil)
+	request.Header.Set("Last-Event-ID", "1")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("Expected text/event-stream, got %q", response.Header.Get("Content-Type"))
+	}
+
+	var ids []string
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		if id, ok := strings.CutPrefix(scanner.Text(), "id: "); ok {
+			ids = append(ids, id)
+		}
+	}
+	if strings.Join(ids, ",") != "2,3" {
+		t.Errorf("Expected to resume with ids 2,3, got %v", ids)
+	}
+
+	finished, _ := http.NewRequest("GET", server.URL+"/sse/4", nil)
+	finished.Header.Set("Last-Event-ID", "3")
+	done, err := http.DefaultClient.Do(finished)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	done.Body.Close()
+	if done.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected 204 once every event was delivered, got %d", done.StatusCode)
+	}
+}
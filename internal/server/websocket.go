@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"echo-api/internal/apigw"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/gorilla/websocket"
+)
+
+// connections tracks open WebSocket connections and delivers posts to them, standing in for the management API
+type connections struct {
+	mu    sync.Mutex
+	conns map[string]*connection
+}
+
+// connection serializes writes to a single WebSocket
+type connection struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+// newConnections creates an empty connection registry
+func newConnections() *connections {
+	return &connections{conns: make(map[string]*connection)}
+}
+
+// PostToConnection writes data to the connection as a text frame, or a binary frame when it is not UTF-8
+func (c *connections) PostToConnection(ctx context.Context, endpoint, connectionID string, data []byte) error {
+	c.mu.Lock()
+	target := c.conns[connectionID]
+	c.mu.Unlock()
+	if target == nil {
+		return apigw.ErrGone
+	}
+
+	messageType := websocket.TextMessage
+	if !utf8.Valid(data) {
+		messageType = websocket.BinaryMessage
+	}
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	if err := target.conn.WriteMessage(messageType, data); err != nil {
+		return apigw.ErrGone
+	}
+	return nil
+}
+
+// add registers an open connection
+func (c *connections) add(connectionID string, conn *websocket.Conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conns[connectionID] = &connection{conn: conn}
+}
+
+// remove forgets a closed connection
+func (c *connections) remove(connectionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.conns, connectionID)
+}
+
+// serveWebSocket runs a connection through the same $connect, $default and $disconnect events API Gateway sends
+func (s *Server) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	connectionID := newConnectionID()
+	connectedAt := time.Now()
+
+	connect := websocketEvent(r, connectionID, connectedAt, "$connect", "CONNECT")
+	response, err := s.websocket.HandleRequest(r.Context(), connect)
+	if err != nil || response.StatusCode != http.StatusOK {
+		http.Error(w, "connection rejected", http.StatusForbidden)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already written the error response
+		return
+	}
+	defer conn.Close()
+
+	s.connections.add(connectionID, conn)
+	defer func() {
+		s.connections.remove(connectionID)
+		disconnect := websocketEvent(r, connectionID, connectedAt, "$disconnect", "DISCONNECT")
+		s.websocket.HandleRequest(context.Background(), disconnect)
+	}()
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		message := websocketEvent(r, connectionID, connectedAt, "$default", "MESSAGE")
+		if messageType == websocket.BinaryMessage {
+			message.Body = base64.StdEncoding.EncodeToString(data)
+			message.IsBase64Encoded = true
+		} else {
+			message.Body = string(data)
+		}
+		s.websocket.HandleRequest(r.Context(), message)
+	}
+}
+
+// websocketEvent builds the WebSocket API event API Gateway would send for the connection
+func websocketEvent(r *http.Request, connectionID string, connectedAt time.Time, routeKey, eventType string) events.APIGatewayWebsocketProxyRequest {
+	now := time.Now()
+	request := proxyRequest(r, nil)
+
+	return events.APIGatewayWebsocketProxyRequest{
+		Headers:               request.Headers,
+		QueryStringParameters: request.QueryStringParameters,
+		RequestContext: events.APIGatewayWebsocketProxyRequestContext{
+			Stage:            "local",
+			RequestID:        strconv.FormatInt(now.UnixNano(), 36),
+			Identity:         request.RequestContext.Identity,
+			ConnectedAt:      connectedAt.UnixMilli(),
+			ConnectionID:     connectionID,
+			DomainName:       r.Host,
+			EventType:        eventType,
+			MessageDirection: "IN",
+			RequestTime:      now.UTC().Format("02/Jan/2006:15:04:05 -0700"),
+			RequestTimeEpoch: now.UnixMilli(),
+			RouteKey:         routeKey,
+		},
+	}
+}
+
+// newConnectionID generates a connection id shaped like API Gateway's
+func newConnectionID() string {
+	id := make([]byte, 10)
+	rand.Read(id)
+	return base64.StdEncoding.EncodeToString(id)
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"echo-api/internal/handler"
+	"echo-api/internal/models"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestServer_WebSocket(t *testing.T) {
+	server := httptest.NewServer(New(handler.NewLambdaHandler()))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws?room=1", nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var connectionID string
+	for _, body := range []string{`{"text":"hi"}`, "plain"} {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(body)); err != nil {
+			t.Fatalf("Failed to send: %v", err)
+		}
+
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("Failed to read echo: %v", err)
+		}
+		if messageType != websocket.TextMessage {
+			t.Errorf("Expected a text frame, got %d", messageType)
+		}
+
+		var message models.WebSocketMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			t.Fatalf("Expected JSON echo: %v", err)
+		}
+		if message.Body != body || message.RouteKey != "$default" {
+			t.Errorf("Unexpected echo %+v", message)
+		}
+		if connectionID == "" {
+			connectionID = message.ConnectionID
+		} else if message.ConnectionID != connectionID {
+			t.Errorf("Expected a stable connection id, got %q and %q", connectionID, message.ConnectionID)
+		}
+		requestContext, _ := message.RequestContext.(map[string]interface{})
+		if requestContext["eventType"] != "MESSAGE" {
+			t.Errorf("Expected the request context in the echo, got %v", message.RequestContext)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package sigv4
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"echo-api/internal/models"
+)
+
+const (
+	// StatusVerified means the signature matched the configured secret
+	StatusVerified = "verified"
+	// StatusFailed means verification was attempted and did not succeed
+	StatusFailed = "failed"
+	// StatusSkipped means no secret is configured, so the signature was not checked
+	StatusSkipped = "skipped"
+
+	// SourceHeader marks a signature carried in the Authorization header
+	SourceHeader = "header"
+	// SourceQuery marks a presigned URL signature
+	SourceQuery = "query"
+
+	// headerWindow is how far the request time may drift from server time for header signatures
+	headerWindow = 15 * time.Minute
+	// maxPresignExpiry is the longest validity SigV4 allows for presigned URLs
+	maxPresignExpiry = 7 * 24 * time.Hour
+)
+
+// Credentials holds an access key, used locally for verification or to sign outgoing requests
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// CredentialsFromEnv reads SIGV4_ACCESS_KEY_ID and SIGV4_SECRET_ACCESS_KEY
+func CredentialsFromEnv() Credentials {
+	return Credentials{
+		AccessKeyID:     os.Getenv("SIGV4_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("SIGV4_SECRET_ACCESS_KEY"),
+	}
+}
+
+// Inspector recomputes SigV4 signatures the way AWS would for echoed requests
+type Inspector struct {
+	credentials Credentials
+	now         func() time.Time
+}
+
+// NewInspector creates a new Inspector; an empty secret disables verification
+func NewInspector(credentials Credentials) *Inspector {
+	return &Inspector{
+		credentials: credentials,
+		now:         time.Now,
+	}
+}
+
+// signedParts holds the fields parsed from a header or presigned query signature
+type signedParts struct {
+	credential    string
+	signedHeaders string
+	signature     string
+	amzDate       string
+	expires       string
+}
+
+// Inspect parses a SigV4 signature from the request, recomputing it against path (the path the client signed); it returns nil when the request is not SigV4 signed
+func (i *Inspector) Inspect(request *models.EchoRequest, path string) *models.SigV4Info {
+	info := &models.SigV4Info{Status: StatusSkipped}
+
+	var parts signedParts
+	query := request.QueryParams
+	if authorization := request.Header("Authorization"); strings.HasPrefix(authorization, Algorithm+" ") {
+		info.Source = SourceHeader
+		parts = parseAuthorization(strings.TrimPrefix(authorization, Algorithm+" "))
+		parts.amzDate = request.Header("X-Amz-Date")
+	} else if algorithm, ok := query["X-Amz-Algorithm"]; ok {
+		info.Source = SourceQuery
+		if algorithm != Algorithm {
+			info.Error = fmt.Sprintf("unsupported algorithm %q", algorithm)
+			return info
+		}
+		parts = signedParts{
+			credential:    query["X-Amz-Credential"],
+			signedHeaders: query["X-Amz-SignedHeaders"],
+			signature:     query["X-Amz-Signature"],
+			amzDate:       query["X-Amz-Date"],
+			expires:       query["X-Amz-Expires"],
+		}
+		query = make(map[string]string, len(request.QueryParams))
+		for key, value := range request.QueryParams {
+			if key != "X-Amz-Signature" {
+				query[key] = value
+			}
+		}
+	} else {
+		return nil
+	}
+
+	info.Signature = parts.signature
+	if parts.credential == "" || parts.signedHeaders == "" || parts.signature == "" {
+		info.Error = "signature must include Credential, SignedHeaders and Signature"
+		return info
+	}
+
+	credential := strings.Split(parts.credential, "/")
+	if len(credential) != 5 || credential[4] != "aws4_request" {
+		info.Error = "credential must be <access-key>/<date>/<region>/<service>/aws4_request"
+		return info
+	}
+	info.AccessKeyID = credential[0]
+	info.Region = credential[2]
+	info.Service = credential[3]
+	info.CredentialScope = strings.Join(credential[1:], "/")
+	info.SignedHeaders = strings.Split(parts.signedHeaders, ";")
+
+	requestTime, err := time.Parse(TimeFormat, parts.amzDate)
+	if err != nil {
+		info.Error = fmt.Sprintf("request time %q must use the %s format", parts.amzDate, TimeFormat)
+		return info
+	}
+	info.RequestTime = requestTime.Format(time.RFC3339)
+	if requestTime.Format(DateFormat) != credential[1] {
+		info.Issues = append(info.Issues, fmt.Sprintf("credential date %s does not match request date %s", credential[1], requestTime.Format(DateFormat)))
+	}
+	i.checkExpiry(info, requestTime, parts.expires)
+
+	info.PayloadHash = i.payloadHash(info, request)
+	canonical, missing := CanonicalRequest(Request{
+		Method:        request.Method,
+		Path:          path,
+		Query:         query,
+		Headers:       request.Headers,
+		SignedHeaders: info.SignedHeaders,
+		PayloadHash:   info.PayloadHash,
+		Service:       info.Service,
+	})
+	for _, name := range missing {
+		info.Issues = append(info.Issues, fmt.Sprintf("signed header %q is not present in the request", name))
+	}
+	info.CanonicalRequest = canonical
+	info.StringToSign = StringToSign(parts.amzDate, info.CredentialScope, canonical)
+
+	i.verify(info, credential[1])
+	return info
+}
+
+// checkExpiry records when the signature stops being accepted
+func (i *Inspector) checkExpiry(info *models.SigV4Info, requestTime time.Time, expires string) {
+	now := i.now()
+	window := headerWindow
+	if info.Source == SourceQuery {
+		seconds, err := strconv.Atoi(expires)
+		if err != nil || seconds < 1 {
+			info.Issues = append(info.Issues, fmt.Sprintf("X-Amz-Expires %q must be a positive number of seconds", expires))
+			return
+		}
+		window = time.Duration(seconds) * time.Second
+		if window > maxPresignExpiry {
+			info.Issues = append(info.Issues, "X-Amz-Expires exceeds the 7 day maximum")
+		}
+	} else if requestTime.After(now.Add(headerWindow)) {
+		info.Issues = append(info.Issues, "request time is more than 15 minutes in the future")
+	}
+
+	expiresAt := requestTime.Add(window)
+	info.ExpiresAt = expiresAt.Format(time.RFC3339)
+	info.Expired = now.After(expiresAt)
+}
+
+// payloadHash returns the payload hash the client declared, or the hash of the body
+func (i *Inspector) payloadHash(info *models.SigV4Info, request *models.EchoRequest) string {
+	body, err := request.SignedBody()
+	if err != nil {
+		body = []byte(request.Body)
+	}
+	bodyHash := HashHex(body)
+
+	declared := request.Header("X-Amz-Content-Sha256")
+	switch {
+	case declared == "":
+		if info.Source == SourceQuery && info.Service == "s3" {
+			return UnsignedPayload
+		}
+		return bodyHash
+	case declared == UnsignedPayload || strings.HasPrefix(declared, "STREAMING-"):
+		return declared
+	case declared != bodyHash:
+		info.Issues = append(info.Issues, "X-Amz-Content-Sha256 does not match the SHA-256 of the body")
+	}
+	return declared
+}
+
+// verify compares the received signature with one computed from the configured secret
+func (i *Inspector) verify(info *models.SigV4Info, date string) {
+	if i.credentials.SecretAccessKey == "" {
+		info.Error = "no secret access key configured"
+		return
+	}
+
+	info.Status = StatusFailed
+	if i.credentials.AccessKeyID != "" && i.credentials.AccessKeyID != info.AccessKeyID {
+		info.Error = fmt.Sprintf("access key %q does not match the configured access key", info.AccessKeyID)
+		return
+	}
+
+	signingKey := SigningKey(i.credentials.SecretAccessKey, date, info.Region, info.Service)
+	expected := Signature(signingKey, info.StringToSign)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(info.Signature))) {
+		info.Error = "signature does not match; compare canonicalRequest and stringToSign with the client"
+		return
+	}
+	info.Status = StatusVerified
+}
+
+// parseAuthorization splits "Credential=..., SignedHeaders=..., Signature=..."
+func parseAuthorization(value string) signedParts {
+	var parts signedParts
+	for _, item := range strings.Split(value, ",") {
+		key, val, _ := strings.Cut(strings.TrimSpace(item), "=")
+		switch key {
+		case "Credential":
+			parts.credential = val
+		case "SignedHeaders":
+			parts.signedHeaders = val
+		case "Signature":
+			parts.signature = val
+		}
+	}
+	return parts
+}
@@ -0,0 +1,93 @@
+package sigv4
+
+import (
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWSCredentialsFromEnv reads the function's own credentials from the variables the Lambda runtime sets
+func AWSCredentialsFromEnv() Credentials {
+	return Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// Signer signs outgoing requests to an AWS service
+type Signer struct {
+	credentials Credentials
+	region      string
+	service     string
+	now         func() time.Time
+}
+
+// NewSigner creates a new Signer for service in region
+func NewSigner(credentials Credentials, region, service string) *Signer {
+	return &Signer{
+		credentials: credentials,
+		region:      region,
+		service:     service,
+		now:         time.Now,
+	}
+}
+
+// Region returns the region requests are signed for
+func (s *Signer) Region() string {
+	return s.region
+}
+
+// Sign adds the X-Amz-* and Authorization headers to request; body must be the exact payload sent
+func (s *Signer) Sign(request *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format(TimeFormat)
+	date := now.Format(DateFormat)
+	payloadHash := HashHex(body)
+
+	// Send the path escaped the same way it is canonicalized
+	request.URL.RawPath = EscapePath(request.URL.Path)
+
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.credentials.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", s.credentials.SessionToken)
+	}
+
+	host := request.Host
+	if host == "" {
+		host = request.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	signed := []string{"host"}
+	for name, values := range request.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.Join(values, ",")
+			signed = append(signed, lower)
+		}
+	}
+	sort.Strings(signed)
+
+	query := make(map[string]string)
+	for name, values := range request.URL.Query() {
+		query[name] = values[0]
+	}
+
+	canonical, _ := CanonicalRequest(Request{
+		Method:        request.Method,
+		Path:          request.URL.Path,
+		Query:         query,
+		Headers:       headers,
+		SignedHeaders: signed,
+		PayloadHash:   payloadHash,
+		Service:       s.service,
+	})
+	scope := strings.Join([]string{date, s.region, s.service, "aws4_request"}, "/")
+	signature := Signature(SigningKey(s.credentials.SecretAccessKey, date, s.region, s.service), StringToSign(amzDate, scope, canonical))
+
+	request.Header.Set("Authorization", Algorithm+" Credential="+s.credentials.AccessKeyID+"/"+scope+
+		", SignedHeaders="+strings.Join(signed, ";")+", Signature="+signature)
+}
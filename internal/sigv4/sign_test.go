@@ -0,0 +1,48 @@
+package sigv4
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"echo-api/internal/models"
+)
+
+func TestSigner_RoundTrip(t *testing.T) {
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	credentials := exampleCredentials
+	credentials.SessionToken = "session-token"
+
+	signer := NewSigner(credentials, "us-east-1", "execute-api")
+	signer.now = func() time.Time { return now }
+
+	body := []byte(`{"hello":"world"}`)
+	request, _ := http.NewRequest("POST", "https://abc.execute-api.us-east-1.amazonaws.com/prod/@connections/L0SM9cOFvHcCIhw=", nil)
+	request.Header.Set("Content-Type", "application/json")
+	signer.Sign(request, body)
+
+	if !strings.Contains(request.URL.String(), "L0SM9cOFvHcCIhw%3D") {
+		t.Errorf("Expected the connection id to be escaped on the wire, got %s", request.URL.String())
+	}
+	if request.Header.Get("X-Amz-Security-Token") != "session-token" {
+		t.Error("Expected the session token header")
+	}
+	if !strings.Contains(request.Header.Get("Authorization"), "SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date;x-amz-security-token,") {
+		t.Errorf("Unexpected signed headers in %q", request.Header.Get("Authorization"))
+	}
+
+	// The inspector recomputes the same signature from the received request
+	headers := map[string]string{"Host": request.URL.Host}
+	for name := range request.Header {
+		headers[name] = request.Header.Get(name)
+	}
+	echo := models.NewEchoRequest("POST", request.URL.Path, headers, map[string]string{}, string(body))
+	info := newInspectorAt(exampleCredentials, now).Inspect(echo, request.URL.Path)
+	if info == nil || info.Status != StatusVerified {
+		t.Fatalf("Expected the signature to verify, got %+v", info)
+	}
+	if info.Service != "execute-api" || info.Region != "us-east-1" {
+		t.Errorf("Unexpected scope %s/%s", info.Region, info.Service)
+	}
+}
@@ -0,0 +1,155 @@
+package sigv4
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+const (
+	// Algorithm is the only signing algorithm SigV4 defines for HMAC credentials
+	Algorithm = "AWS4-HMAC-SHA256"
+	// TimeFormat is the layout of X-Amz-Date values
+	TimeFormat = "20060102T150405Z"
+	// DateFormat is the layout of the date in a credential scope
+	DateFormat = "20060102"
+	// UnsignedPayload is the payload hash clients send when the body is not signed
+	UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// Request holds the parts of an HTTP request that take part in signing
+type Request struct {
+	Method        string
+	Path          string
+	Query         map[string]string
+	Headers       map[string]string
+	SignedHeaders []string
+	PayloadHash   string
+	Service       string
+}
+
+// CanonicalRequest builds the canonical request string; it also returns the signed headers missing from the request
+func CanonicalRequest(request Request) (string, []string) {
+	headers, missing := canonicalHeaders(request.Headers, request.SignedHeaders)
+
+	return strings.Join([]string{
+		request.Method,
+		CanonicalURI(request.Path, request.Service),
+		CanonicalQuery(request.Query),
+		headers,
+		strings.Join(request.SignedHeaders, ";"),
+		request.PayloadHash,
+	}, "\n"), missing
+}
+
+// CanonicalURI encodes each path segment, twice for every service except S3
+func CanonicalURI(path, service string) string {
+	if path == "" {
+		return "/"
+	}
+	uri := EscapePath(path)
+	if service != "s3" {
+		uri = EscapePath(uri)
+	}
+	return uri
+}
+
+// CanonicalQuery encodes and sorts the query parameters
+func CanonicalQuery(query map[string]string) string {
+	pairs := make([]string, 0, len(query))
+	for key, value := range query {
+		pairs = append(pairs, Escape(key)+"="+Escape(value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// canonicalHeaders renders the signed headers as lowercase name:value lines
+func canonicalHeaders(headers map[string]string, signed []string) (string, []string) {
+	lookup := make(map[string]string, len(headers))
+	for key, value := range headers {
+		lookup[strings.ToLower(key)] = value
+	}
+
+	var builder strings.Builder
+	var missing []string
+	for _, name := range signed {
+		value, ok := lookup[name]
+		if !ok {
+			missing = append(missing, name)
+		}
+		builder.WriteString(name)
+		builder.WriteString(":")
+		builder.WriteString(strings.Join(strings.Fields(value), " "))
+		builder.WriteString("\n")
+	}
+	return builder.String(), missing
+}
+
+// StringToSign builds the string that is signed for a canonical request
+func StringToSign(amzDate, scope, canonicalRequest string) string {
+	return strings.Join([]string{
+		Algorithm,
+		amzDate,
+		scope,
+		HashHex([]byte(canonicalRequest)),
+	}, "\n")
+}
+
+// SigningKey derives the signing key for a date, region and service
+func SigningKey(secret, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+// Signature signs stringToSign with the signing key and hex encodes the result
+func Signature(signingKey []byte, stringToSign string) string {
+	return hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+}
+
+// HashHex returns the hex encoded SHA-256 of data
+func HashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Escape percent-encodes everything except RFC 3986 unreserved characters
+func Escape(value string) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if isUnreserved(c) {
+			builder.WriteByte(c)
+			continue
+		}
+		builder.WriteString("%")
+		builder.WriteString(strings.ToUpper(hex.EncodeToString([]byte{c})))
+	}
+	return builder.String()
+}
+
+// EscapePath percent-encodes each segment of a path, keeping the slashes
+func EscapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = Escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// isUnreserved reports whether c may appear unencoded
+func isUnreserved(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// hmacSHA256 computes HMAC-SHA256 of data with key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
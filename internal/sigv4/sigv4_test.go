@@ -0,0 +1,70 @@
+package sigv4
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"abc-._~XYZ019", "abc-._~XYZ019"},
+		{"a b", "a%20b"},
+		{"a/b+c=d", "a%2Fb%2Bc%3Dd"},
+		{"日本", "%E6%97%A5%E6%9C%AC"},
+	}
+
+	for _, tc := range testCases {
+		if result := Escape(tc.input); result != tc.expected {
+			t.Errorf("For %q, expected %q, got %q", tc.input, tc.expected, result)
+		}
+	}
+}
+
+func TestCanonicalURI(t *testing.T) {
+	if uri := CanonicalURI("", "execute-api"); uri != "/" {
+		t.Errorf("Expected / for empty path, got %s", uri)
+	}
+	if uri := CanonicalURI("/a b/c", "s3"); uri != "/a%20b/c" {
+		t.Errorf("Expected single encoding for s3, got %s", uri)
+	}
+	if uri := CanonicalURI("/a b/c", "execute-api"); uri != "/a%2520b/c" {
+		t.Errorf("Expected double encoding for other services, got %s", uri)
+	}
+}
+
+func TestCanonicalRequest(t *testing.T) {
+	canonical, missing := CanonicalRequest(Request{
+		Method:        "GET",
+		Path:          "/",
+		Query:         map[string]string{"Param2": "value2", "Param1": "value1"},
+		Headers:       map[string]string{"Host": "example.amazonaws.com", "X-Amz-Date": "20150830T123600Z"},
+		SignedHeaders: []string{"host", "x-amz-date", "x-amz-target"},
+		PayloadHash:   HashHex(nil),
+		Service:       "service",
+	})
+
+	lines := strings.Split(canonical, "\n")
+	if lines[2] != "Param1=value1&Param2=value2" {
+		t.Errorf("Expected sorted query, got %s", lines[2])
+	}
+	if lines[3] != "host:example.amazonaws.com" {
+		t.Errorf("Expected lowercase host header line, got %s", lines[3])
+	}
+	if len(missing) != 1 || missing[0] != "x-amz-target" {
+		t.Errorf("Expected x-amz-target to be reported missing, got %v", missing)
+	}
+}
+
+func TestSigningKey(t *testing.T) {
+	// Signing key example from the AWS SigV4 documentation
+	key := SigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	expected := "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"
+
+	if encoded := hex.EncodeToString(key); encoded != expected {
+		t.Errorf("Expected signing key %s, got %s", expected, encoded)
+	}
+}
@@ -0,0 +1,61 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Event is a single server-sent event
+type Event struct {
+	ID   string
+	Name string
+	Data string
+}
+
+// WriteTo writes the event in text/event-stream framing; multi-line data becomes several data fields
+func (e Event) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	if e.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", e.ID)
+	}
+	if e.Name != "" {
+		fmt.Fprintf(&b, "event: %s\n", e.Name)
+	}
+	for _, line := range strings.Split(e.Data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// EventStream writes the events with sequence numbers first to last-1, waiting interval between them;
+// a positive retry is sent first as the client's reconnection delay
+func EventStream(first, last int, interval, retry time.Duration, event func(sequence int) (Event, error)) func(ctx context.Context, w io.Writer) error {
+	return func(ctx context.Context, w io.Writer) error {
+		if retry > 0 {
+			if _, err := fmt.Fprintf(w, "retry: %d\n\n", retry.Milliseconds()); err != nil {
+				return err
+			}
+		}
+		for sequence := first; sequence < last; sequence++ {
+			if sequence > first {
+				if err := Sleep(ctx, interval); err != nil {
+					return err
+				}
+			}
+			e, err := event(sequence)
+			if err != nil {
+				return err
+			}
+			if _, err := e.WriteTo(w); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,106 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Response is a streamed HTTP response: the status and headers are sent first, then Body writes
+// the payload piece by piece until it returns
+type Response struct {
+	StatusCode int
+	Headers    map[string]string
+	Body       func(ctx context.Context, w io.Writer) error
+}
+
+// Buffered creates a Response whose body is already complete
+func Buffered(statusCode int, headers map[string]string, body []byte) *Response {
+	return &Response{
+		StatusCode: statusCode,
+		Headers:    headers,
+		Body: func(ctx context.Context, w io.Writer) error {
+			_, err := w.Write(body)
+			return err
+		},
+	}
+}
+
+// Reader runs the body in the background and returns a reader over what it writes; the body
+// stops when ctx is done or the reader is closed
+func (r *Response) Reader(ctx context.Context) io.ReadCloser {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(r.Body(ctx, writer))
+	}()
+	return reader
+}
+
+// Sleep waits for d, returning early with the context error when ctx is done
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// NDJSON writes n newline-delimited JSON records, waiting interval between them
+func NDJSON(n int, interval time.Duration, record func(sequence int) interface{}) func(ctx context.Context, w io.Writer) error {
+	return func(ctx context.Context, w io.Writer) error {
+		for sequence := 0; sequence < n; sequence++ {
+			if sequence > 0 {
+				if err := Sleep(ctx, interval); err != nil {
+					return err
+				}
+			}
+			line, err := json.Marshal(record(sequence))
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(append(line, '\n')); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Drip writes numBytes bytes spread evenly over duration, after an initial delay
+func Drip(numBytes int, duration, delay time.Duration) func(ctx context.Context, w io.Writer) error {
+	return func(ctx context.Context, w io.Writer) error {
+		if err := Sleep(ctx, delay); err != nil {
+			return err
+		}
+		if numBytes == 0 {
+			return nil
+		}
+		interval := duration / time.Duration(numBytes)
+		for i := 0; i < numBytes; i++ {
+			if _, err := w.Write([]byte{'*'}); err != nil {
+				return err
+			}
+			if i < numBytes-1 {
+				if err := Sleep(ctx, interval); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// Collect runs the body to completion and returns everything it wrote
+func (r *Response) Collect(ctx context.Context) ([]byte, error) {
+	var buf bytes.Buffer
+	err := r.Body(ctx, &buf)
+	return buf.Bytes(), err
+}
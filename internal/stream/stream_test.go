@@ -0,0 +1,83 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNDJSON(t *testing.T) {
+	response := &Response{Body: NDJSON(3, 0, func(sequence int) interface{} {
+		return map[string]int{"sequence": sequence}
+	})}
+
+	body, err := response.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d", len(lines))
+	}
+	for i, line := range lines {
+		var record map[string]int
+		if err := json.Unmarshal([]byte(line), &record); err != nil || record["sequence"] != i {
+			t.Errorf("Expected sequence %d, got %q", i, line)
+		}
+	}
+}
+
+func TestDrip(t *testing.T) {
+	response := &Response{Body: Drip(5, 20*time.Millisecond, 10*time.Millisecond)}
+
+	started := time.Now()
+	reader := response.Reader(context.Background())
+	defer reader.Close()
+
+	first := make([]byte, 1)
+	if _, err := io.ReadFull(reader, first); err != nil {
+		t.Fatalf("Failed to read first byte: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed < 10*time.Millisecond {
+		t.Errorf("Expected the first byte after the delay, got it after %v", elapsed)
+	}
+
+	rest, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(first)+string(rest) != "*****" {
+		t.Errorf("Expected 5 bytes, got %q", string(first)+string(rest))
+	}
+}
+
+func TestDrip_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	response := &Response{Body: Drip(5, time.Hour, 0)}
+	if _, err := response.Collect(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	response := &Response{Body: EventStream(2, 4, 0, 1500*time.Millisecond, func(sequence int) (Event, error) {
+		return Event{ID: strconv.Itoa(sequence), Name: "tick", Data: "line\nnext"}, nil
+	})}
+
+	body, err := response.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "retry: 1500\n\n" +
+		"id: 2\nevent: tick\ndata: line\ndata: next\n\n" +
+		"id: 3\nevent: tick\ndata: line\ndata: next\n\n"
+	if string(body) != expected {
+		t.Errorf("Expected %q, got %q", expected, body)
+	}
+}
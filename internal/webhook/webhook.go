@@ -0,0 +1,305 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"echo-api/internal/models"
+)
+
+const (
+	// SchemeGitHub verifies X-Hub-Signature-256 (or the legacy X-Hub-Signature)
+	SchemeGitHub = "github"
+	// SchemeStripe verifies Stripe-Signature with its embedded timestamp
+	SchemeStripe = "stripe"
+	// SchemeSlack verifies X-Slack-Signature with X-Slack-Request-Timestamp
+	SchemeSlack = "slack"
+	// SchemeGeneric verifies a configurable HMAC header
+	SchemeGeneric = "hmac"
+
+	// DefaultTolerance is the accepted clock skew for timestamped schemes
+	DefaultTolerance = 5 * time.Minute
+)
+
+// Config holds the secrets and settings for each signature scheme
+type Config struct {
+	GitHubSecret string
+	StripeSecret string
+	SlackSecret  string
+	Tolerance    time.Duration
+	Generic      GenericConfig
+}
+
+// GenericConfig describes a custom HMAC header scheme
+type GenericConfig struct {
+	Header    string
+	Secret    string
+	Algorithm string
+	Prefix    string
+	Encoding  string
+}
+
+// ConfigFromEnv builds a Config from WEBHOOK_* environment variables
+func ConfigFromEnv() (Config, error) {
+	config := Config{
+		GitHubSecret: os.Getenv("WEBHOOK_GITHUB_SECRET"),
+		StripeSecret: os.Getenv("WEBHOOK_STRIPE_SECRET"),
+		SlackSecret:  os.Getenv("WEBHOOK_SLACK_SECRET"),
+		Tolerance:    DefaultTolerance,
+		Generic: GenericConfig{
+			Header:    os.Getenv("WEBHOOK_HMAC_HEADER"),
+			Secret:    os.Getenv("WEBHOOK_HMAC_SECRET"),
+			Algorithm: os.Getenv("WEBHOOK_HMAC_ALGORITHM"),
+			Prefix:    os.Getenv("WEBHOOK_HMAC_PREFIX"),
+			Encoding:  os.Getenv("WEBHOOK_HMAC_ENCODING"),
+		},
+	}
+
+	if value := os.Getenv("WEBHOOK_TIMESTAMP_TOLERANCE"); value != "" {
+		tolerance, err := time.ParseDuration(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid WEBHOOK_TIMESTAMP_TOLERANCE: %w", err)
+		}
+		config.Tolerance = tolerance
+	}
+	return config, nil
+}
+
+// Verifier checks webhook-style HMAC signatures on echoed requests
+type Verifier struct {
+	config Config
+	now    func() time.Time
+}
+
+// NewVerifier creates a new Verifier instance
+func NewVerifier(config Config) *Verifier {
+	if config.Tolerance <= 0 {
+		config.Tolerance = DefaultTolerance
+	}
+	return &Verifier{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Verify detects the signature scheme from the request headers and checks it; it returns nil when no signature header is present
+func (v *Verifier) Verify(request *models.EchoRequest) *models.WebhookSignature {
+	if value := request.Header("X-Hub-Signature-256"); value != "" {
+		return v.verifyGitHub(request, "X-Hub-Signature-256", value, "sha256=", sha256.New)
+	}
+	if value := request.Header("X-Hub-Signature"); value != "" {
+		return v.verifyGitHub(request, "X-Hub-Signature", value, "sha1=", sha1.New)
+	}
+	if value := request.Header("Stripe-Signature"); value != "" {
+		return v.verifyStripe(request, value)
+	}
+	if value := request.Header("X-Slack-Signature"); value != "" {
+		return v.verifySlack(request, value)
+	}
+	if header := v.config.Generic.Header; header != "" {
+		if value := request.Header(header); value != "" {
+			return v.verifyGeneric(request, value)
+		}
+	}
+	return nil
+}
+
+// verifyGitHub checks a GitHub signature of the form sha256=<hex>
+func (v *Verifier) verifyGitHub(request *models.EchoRequest, header, value, prefix string, algorithm func() hash.Hash) *models.WebhookSignature {
+	result := &models.WebhookSignature{Scheme: SchemeGitHub, Header: header}
+	if v.config.GitHubSecret == "" {
+		result.Reason = "secret not configured (WEBHOOK_GITHUB_SECRET)"
+		return result
+	}
+	if !strings.HasPrefix(value, prefix) {
+		result.Reason = fmt.Sprintf("header value must start with %q", prefix)
+		return result
+	}
+
+	received, err := hex.DecodeString(strings.TrimPrefix(value, prefix))
+	if err != nil {
+		result.Reason = "signature is not valid hex"
+		return result
+	}
+	v.compare(result, received, sign(algorithm, v.config.GitHubSecret, payload(request)))
+	return result
+}
+
+// verifyStripe checks a Stripe signature of the form t=<unix>,v1=<hex>[,v1=<hex>...]
+func (v *Verifier) verifyStripe(request *models.EchoRequest, value string) *models.WebhookSignature {
+	result := &models.WebhookSignature{Scheme: SchemeStripe, Header: "Stripe-Signature"}
+	if v.config.StripeSecret == "" {
+		result.Reason = "secret not configured (WEBHOOK_STRIPE_SECRET)"
+		return result
+	}
+
+	var timestamp string
+	var signatures [][]byte
+	for _, item := range strings.Split(value, ",") {
+		key, val, _ := strings.Cut(strings.TrimSpace(item), "=")
+		switch key {
+		case "t":
+			timestamp = val
+		case "v1":
+			if decoded, err := hex.DecodeString(val); err == nil {
+				signatures = append(signatures, decoded)
+			}
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		result.Reason = "header must contain t= and at least one v1= entry"
+		return result
+	}
+	if !v.checkTimestamp(result, timestamp) {
+		return result
+	}
+
+	expected := sign(sha256.New, v.config.StripeSecret, timestamp+"."+payload(request))
+	for _, received := range signatures {
+		if hmac.Equal(received, expected) {
+			result.Verified = true
+			return result
+		}
+	}
+	result.Reason = "no v1 signature matches the computed signature of t.body"
+	return result
+}
+
+// verifySlack checks a Slack signature of the form v0=<hex> over v0:<timestamp>:<body>
+func (v *Verifier) verifySlack(request *models.EchoRequest, value string) *models.WebhookSignature {
+	result := &models.WebhookSignature{Scheme: SchemeSlack, Header: "X-Slack-Signature"}
+	if v.config.SlackSecret == "" {
+		result.Reason = "secret not configured (WEBHOOK_SLACK_SECRET)"
+		return result
+	}
+	if !strings.HasPrefix(value, "v0=") {
+		result.Reason = `header value must start with "v0="`
+		return result
+	}
+
+	timestamp := request.Header("X-Slack-Request-Timestamp")
+	if timestamp == "" {
+		result.Reason = "X-Slack-Request-Timestamp header is missing"
+		return result
+	}
+	if !v.checkTimestamp(result, timestamp) {
+		return result
+	}
+
+	received, err := hex.DecodeString(strings.TrimPrefix(value, "v0="))
+	if err != nil {
+		result.Reason = "signature is not valid hex"
+		return result
+	}
+	v.compare(result, received, sign(sha256.New, v.config.SlackSecret, "v0:"+timestamp+":"+payload(request)))
+	return result
+}
+
+// verifyGeneric checks the configured custom HMAC header
+func (v *Verifier) verifyGeneric(request *models.EchoRequest, value string) *models.WebhookSignature {
+	generic := v.config.Generic
+	result := &models.WebhookSignature{Scheme: SchemeGeneric, Header: generic.Header}
+	if generic.Secret == "" {
+		result.Reason = "secret not configured (WEBHOOK_HMAC_SECRET)"
+		return result
+	}
+
+	algorithm, err := hashByName(generic.Algorithm)
+	if err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+	if generic.Prefix != "" && !strings.HasPrefix(value, generic.Prefix) {
+		result.Reason = fmt.Sprintf("header value must start with %q", generic.Prefix)
+		return result
+	}
+
+	encoded := strings.TrimPrefix(value, generic.Prefix)
+	encoding := strings.ToLower(generic.Encoding)
+	if encoding == "" {
+		encoding = "hex"
+	}
+	var received []byte
+	switch encoding {
+	case "hex":
+		received, err = hex.DecodeString(encoded)
+	case "base64":
+		received, err = base64.StdEncoding.DecodeString(encoded)
+	default:
+		result.Reason = fmt.Sprintf("unsupported encoding %q", generic.Encoding)
+		return result
+	}
+	if err != nil {
+		result.Reason = "signature is not valid " + encoding
+		return result
+	}
+	v.compare(result, received, sign(algorithm, generic.Secret, payload(request)))
+	return result
+}
+
+// checkTimestamp records the timestamp and rejects it when outside the tolerance
+func (v *Verifier) checkTimestamp(result *models.WebhookSignature, timestamp string) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		result.Reason = fmt.Sprintf("timestamp %q is not a unix time", timestamp)
+		return false
+	}
+	signedAt := time.Unix(seconds, 0)
+	result.Timestamp = signedAt.UTC().Format(time.RFC3339)
+
+	skew := v.now().Sub(signedAt)
+	if math.Abs(skew.Seconds()) > v.config.Tolerance.Seconds() {
+		result.Reason = fmt.Sprintf("timestamp is %s away from server time, tolerance is %s", skew.Round(time.Second), v.config.Tolerance)
+		return false
+	}
+	return true
+}
+
+// compare marks the result verified when the signatures match
+func (v *Verifier) compare(result *models.WebhookSignature, received, expected []byte) {
+	if hmac.Equal(received, expected) {
+		result.Verified = true
+		return
+	}
+	result.Reason = "signature does not match the computed signature of the body"
+}
+
+// payload returns the body bytes that were signed
+func payload(request *models.EchoRequest) string {
+	body, err := request.SignedBody()
+	if err != nil {
+		return request.Body
+	}
+	return string(body)
+}
+
+// sign computes the HMAC of payload with secret
+func sign(algorithm func() hash.Hash, secret, payload string) []byte {
+	mac := hmac.New(algorithm, []byte(secret))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// hashByName maps a configured algorithm name to its hash constructor
+func hashByName(name string) (func() hash.Hash, error) {
+	switch strings.ToLower(name) {
+	case "", "sha256":
+		return sha256.New, nil
+	case "sha1":
+		return sha1.New, nil
+	case "sha512":
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("unsupported algorithm %q", name)
+}
@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"echo-api/internal/models"
+)
+
+// hexHMAC computes a hex encoded HMAC-SHA256 for tests
+func hexHMAC(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newRequest creates an echo request carrying the given headers and body
+func newRequest(headers map[string]string, body string) *models.EchoRequest {
+	return models.NewEchoRequest("POST", "/webhook", headers, map[string]string{}, body)
+}
+
+func TestVerify_NoSignatureHeader(t *testing.T) {
+	verifier := NewVerifier(Config{})
+
+	if result := verifier.Verify(newRequest(map[string]string{"Content-Type": "application/json"}, "{}")); result != nil {
+		t.Errorf("Expected nil result without signature headers, got %+v", result)
+	}
+}
+
+func TestVerify_GitHub(t *testing.T) {
+	body := `{"action":"opened"}`
+	verifier := NewVerifier(Config{GitHubSecret: "gh-secret"})
+
+	result := verifier.Verify(newRequest(map[string]string{"x-hub-signature-256": "sha256=" + hexHMAC("gh-secret", body)}, body))
+	if result == nil || result.Scheme != SchemeGitHub || !result.Verified {
+		t.Fatalf("Expected verified github signature, got %+v", result)
+	}
+
+	result = verifier.Verify(newRequest(map[string]string{"X-Hub-Signature-256": "sha256=" + hexHMAC("other", body)}, body))
+	if result.Verified || result.Reason == "" {
+		t.Errorf("Expected mismatch with a reason, got %+v", result)
+	}
+
+	result = NewVerifier(Config{}).Verify(newRequest(map[string]string{"X-Hub-Signature-256": "sha256=00"}, body))
+	if result.Verified || !strings.Contains(result.Reason, "not configured") {
+		t.Errorf("Expected missing secret reason, got %+v", result)
+	}
+}
+
+func TestVerify_Stripe(t *testing.T) {
+	body := `{"id":"evt_1"}`
+	now := time.Unix(1700000000, 0)
+	verifier := NewVerifier(Config{StripeSecret: "whsec_test"})
+	verifier.now = func() time.Time { return now }
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	header := "t=" + timestamp + ",v1=deadbeef,v1=" + hexHMAC("whsec_test", timestamp+"."+body)
+	result := verifier.Verify(newRequest(map[string]string{"Stripe-Signature": header}, body))
+	if result == nil || result.Scheme != SchemeStripe || !result.Verified {
+		t.Fatalf("Expected verified stripe signature, got %+v", result)
+	}
+
+	stale := strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10)
+	header = "t=" + stale + ",v1=" + hexHMAC("whsec_test", stale+"."+body)
+	result = verifier.Verify(newRequest(map[string]string{"Stripe-Signature": header}, body))
+	if result.Verified || !strings.Contains(result.Reason, "tolerance") {
+		t.Errorf("Expected tolerance failure, got %+v", result)
+	}
+}
+
+func TestVerify_Slack(t *testing.T) {
+	body := "token=abc&team_id=T1"
+	now := time.Unix(1700000000, 0)
+	verifier := NewVerifier(Config{SlackSecret: "slack-secret"})
+	verifier.now = func() time.Time { return now }
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	headers := map[string]string{
+		"X-Slack-Signature":         "v0=" + hexHMAC("slack-secret", "v0:"+timestamp+":"+body),
+		"X-Slack-Request-Timestamp": timestamp,
+	}
+	result := verifier.Verify(newRequest(headers, body))
+	if result == nil || result.Scheme != SchemeSlack || !result.Verified {
+		t.Fatalf("Expected verified slack signature, got %+v", result)
+	}
+	if result.Timestamp == "" {
+		t.Error("Expected timestamp to be reported")
+	}
+}
+
+func TestVerify_Generic(t *testing.T) {
+	body := "payload"
+	mac := hmac.New(sha256.New, []byte("generic"))
+	mac.Write([]byte(body))
+
+	verifier := NewVerifier(Config{Generic: GenericConfig{
+		Header:   "X-Signature",
+		Secret:   "generic",
+		Prefix:   "hmac ",
+		Encoding: "base64",
+	}})
+
+	result := verifier.Verify(newRequest(map[string]string{"X-Signature": "hmac " + base64.StdEncoding.EncodeToString(mac.Sum(nil))}, body))
+	if result == nil || result.Scheme != SchemeGeneric || !result.Verified {
+		t.Fatalf("Expected verified generic signature, got %+v", result)
+	}
+
+	result = verifier.Verify(newRequest(map[string]string{"X-Signature": "sha256 abc"}, body))
+	if result.Verified || !strings.Contains(result.Reason, "must start with") {
+		t.Errorf("Expected prefix failure, got %+v", result)
+	}
+
+	// The default encoding is named in the reason
+	verifier = NewVerifier(Config{Generic: GenericConfig{Header: "X-Signature", Secret: "generic"}})
+	result = verifier.Verify(newRequest(map[string]string{"X-Signature": "zz"}, body))
+	if result.Reason != "signature is not valid hex" {
+		t.Errorf("Expected a hex decoding failure, got %q", result.Reason)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("WEBHOOK_GITHUB_SECRET", "gh")
+	t.Setenv("WEBHOOK_TIMESTAMP_TOLERANCE", "30s")
+
+	config, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.GitHubSecret != "gh" || config.Tolerance != 30*time.Second {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+
+	t.Setenv("WEBHOOK_TIMESTAMP_TOLERANCE", "soon")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Error("Expected error for invalid tolerance")
+	}
+}